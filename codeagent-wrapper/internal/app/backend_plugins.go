@@ -0,0 +1,17 @@
+package wrapper
+
+import (
+	ibackend "codeagent-wrapper/internal/backend"
+)
+
+// loadBackendPlugins scans ibackend.DefaultPluginDir for plugin backend
+// manifests and registers them. It is best-effort: a missing directory is
+// not an error, and a malformed manifest is only logged as an error (through
+// the logError hook set via ibackend.SetLogFuncs) rather than failing the
+// command.
+func loadBackendPlugins() {
+	ibackend.SetLogFuncs(logWarn, logError)
+	if _, err := ibackend.LoadPluginsFromDefaultDir(); err != nil {
+		logWarn("backend plugins: " + err.Error())
+	}
+}
@@ -3,6 +3,7 @@ package wrapper
 import (
 	"bytes"
 	"os"
+	"runtime"
 	"testing"
 
 	config "codeagent-wrapper/internal/config"
@@ -68,6 +69,91 @@ func BenchmarkJSONParse_ParseJSONStreamInternal(b *testing.B) {
 	}
 }
 
+// BenchmarkJSONParse_ParseJSONStreamTokenized measures the token-walking
+// parser (ParseJSONStreamTokenized) on the same small stream as
+// BenchmarkJSONParse_ParseJSONStreamInternal, for a like-for-like ns/op and
+// allocs/op comparison between the two parsing strategies.
+func BenchmarkJSONParse_ParseJSONStreamTokenized(b *testing.B) {
+	stream := []byte(
+		`{"type":"thread.started","thread_id":"t"}` + "\n" +
+			`{"type":"item.completed","item":{"type":"agent_message","text":"hello"}}` + "\n" +
+			`{"type":"thread.completed","thread_id":"t"}` + "\n",
+	)
+	b.SetBytes(int64(len(stream)))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		message, threadID := parseJSONStreamTokenized(bytes.NewReader(stream), nil, nil, nil, nil)
+		benchMessageSink = message
+		benchThreadIDSink = threadID
+	}
+}
+
+// BenchmarkJSONParse_ParseJSONStreamTokenized_100kEvents feeds a multi-MB
+// stream of 100k item.completed events through the tokenized parser, to
+// demonstrate its allocations stay bounded (it only fully decodes each
+// event's small "item" sub-object) rather than growing with the stream's
+// total size the way buffering the whole thing up front would.
+func BenchmarkJSONParse_ParseJSONStreamTokenized_100kEvents(b *testing.B) {
+	const eventCount = 100_000
+	var sb bytes.Buffer
+	sb.Grow(eventCount * 96)
+	for i := 0; i < eventCount; i++ {
+		sb.WriteString(`{"type":"item.completed","item":{"type":"agent_message","text":"event payload"}}` + "\n")
+	}
+	stream := sb.Bytes()
+	b.SetBytes(int64(len(stream)))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		message, threadID := parseJSONStreamTokenized(bytes.NewReader(stream), nil, nil, nil, nil)
+		benchMessageSink = message
+		benchThreadIDSink = threadID
+	}
+}
+
+// BenchmarkJSONParse_ParseJSONStreamInternal_OpenAISSE measures the added
+// cost of stripSSEDataPrefix plus the OpenAI chat.completion.chunk parser,
+// against a stream shaped like BenchmarkJSONParse_ParseJSONStreamInternal's
+// but SSE-framed.
+func BenchmarkJSONParse_ParseJSONStreamInternal_OpenAISSE(b *testing.B) {
+	stream := []byte(
+		`data: {"object":"chat.completion.chunk","choices":[{"delta":{"content":"hello"}}]}` + "\n" +
+			`data: {"object":"chat.completion.chunk","choices":[{"delta":{"content":""},"finish_reason":"stop"}]}` + "\n" +
+			"data: [DONE]\n",
+	)
+	b.SetBytes(int64(len(stream)))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		message, threadID := parseJSONStreamInternal(bytes.NewReader(stream), nil, nil, nil, nil)
+		benchMessageSink = message
+		benchThreadIDSink = threadID
+	}
+}
+
+// BenchmarkJSONParse_ParseJSONStreamInternal_GenericNDJSON measures the
+// generic NDJSON passthrough parser forced on via --stream-format, for a
+// like-for-like comparison with the backend-specific parsers above.
+func BenchmarkJSONParse_ParseJSONStreamInternal_GenericNDJSON(b *testing.B) {
+	restore := setForcedStreamParser("ndjson")
+	defer restore()
+
+	stream := []byte(
+		`{"text":"hello"}` + "\n" +
+			`{"text":" world"}` + "\n",
+	)
+	b.SetBytes(int64(len(stream)))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		message, threadID := parseJSONStreamInternal(bytes.NewReader(stream), nil, nil, nil, nil)
+		benchMessageSink = message
+		benchThreadIDSink = threadID
+	}
+}
+
 // BenchmarkLoggerWrite 测试日志写入性能
 func BenchmarkLoggerWrite(b *testing.B) {
 	logger, err := NewLogger()
@@ -101,3 +187,68 @@ func BenchmarkLoggerConcurrentWrite(b *testing.B) {
 	b.StopTimer()
 	logger.Flush()
 }
+
+// BenchmarkLoggerConcurrentWriteDropNewest pins GOMAXPROCS producers against
+// a Logger in PolicyDropNewest mode with a deliberately shallow queue, to
+// show throughput holds roughly flat as producer count scales - unlike the
+// default PolicyBlock path (BenchmarkLoggerConcurrentWrite), which contends
+// on a full channel send once producers outrun the single writer goroutine.
+func BenchmarkLoggerConcurrentWriteDropNewest(b *testing.B) {
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	logger, err := NewLogger(WithQueuePolicy(PolicyDropNewest, 64))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer logger.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("concurrent benchmark log message")
+		}
+	})
+	b.StopTimer()
+	logger.Flush()
+}
+
+// BenchmarkLoggerWriteStructured measures the structured-field path
+// (InfoFields + typed Field helpers) against BenchmarkLoggerWrite's plain
+// Info(string), so a regression in either encoding can be told apart from
+// one in the shared channel/writer plumbing.
+func BenchmarkLoggerWriteStructured(b *testing.B) {
+	logger, err := NewLoggerWithFormat(FormatJSON)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer logger.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.InfoFields("benchmark log message", String("component", "bench"), Int("i", i))
+	}
+	b.StopTimer()
+	logger.Flush()
+}
+
+// BenchmarkLoggerConcurrentWriteStructured is BenchmarkLoggerConcurrentWrite's
+// structured-field counterpart.
+func BenchmarkLoggerConcurrentWriteStructured(b *testing.B) {
+	logger, err := NewLoggerWithFormat(FormatJSON)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer logger.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.InfoFields("concurrent benchmark log message", String("component", "bench"))
+		}
+	})
+	b.StopTimer()
+	logger.Flush()
+}
@@ -0,0 +1,125 @@
+package wrapper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cache "codeagent-wrapper/internal/cache"
+
+	"github.com/spf13/cobra"
+)
+
+// newCacheCommand returns the `cache` parent command, currently offering
+// only `cache prune` for the content-addressed task result cache.
+func newCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "cache",
+		Short:         "Inspect or maintain the task result cache",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	cmd.AddCommand(newCachePruneCommand())
+	return cmd
+}
+
+func newCachePruneCommand() *cobra.Command {
+	var olderThan string
+	var maxSize string
+
+	cmd := &cobra.Command{
+		Use:           "prune",
+		Short:         "Remove stale or excess entries from the task result cache",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			age, err := parseDurationWithDays(olderThan)
+			if err != nil {
+				return fmt.Errorf("--older-than: %w", err)
+			}
+			maxBytes, err := parseByteSize(maxSize)
+			if err != nil {
+				return fmt.Errorf("--max-size: %w", err)
+			}
+
+			dir, err := cache.DefaultResultCacheDir()
+			if err != nil {
+				return err
+			}
+			rc, err := cache.NewResultCache(dir)
+			if err != nil {
+				return err
+			}
+
+			removed, freed, err := rc.Prune(age, maxBytes)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Pruned %d cache entries, freed %d bytes\n", removed, freed)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Remove entries last written before this long ago, e.g. 7d, 24h")
+	cmd.Flags().StringVar(&maxSize, "max-size", "", "After age-pruning, remove oldest entries until the cache is under this size, e.g. 10GB")
+
+	return cmd
+}
+
+// parseDurationWithDays parses durations accepted by time.ParseDuration plus
+// a "d" (day) suffix, e.g. "7d". An empty string means no age limit.
+func parseDurationWithDays(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(raw, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(raw, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid day duration %q", raw)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(raw)
+}
+
+var byteSizeUnits = map[string]int64{
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+}
+
+// parseByteSize parses sizes like "10GB", "512MB", "100" (bytes). An empty
+// string means no size limit.
+func parseByteSize(raw string) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	raw = strings.ToLower(raw)
+
+	i := 0
+	for i < len(raw) && (raw[i] == '.' || (raw[i] >= '0' && raw[i] <= '9')) {
+		i++
+	}
+	if i == 0 {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+	value, err := strconv.ParseFloat(raw[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+
+	unit := strings.TrimSpace(raw[i:])
+	if unit == "" {
+		unit = "b"
+	}
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+	return int64(value * float64(multiplier)), nil
+}
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	config "codeagent-wrapper/internal/config"
+	_ "codeagent-wrapper/internal/config/builtins" // registers the built-in agent presets
 
+	"github.com/goccy/go-json"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -30,13 +32,20 @@ type cliOptions struct {
 	Agent           string
 	PromptFile      string
 	SkipPermissions bool
+	Profile         string
 
-	Parallel   bool
-	FullOutput bool
+	Parallel        bool
+	FullOutput      bool
+	OutputFormat    string
+	BackendFallback string
 
 	Cleanup    bool
 	Version    bool
 	ConfigFile string
+
+	MetricsAddr string
+
+	StreamFormat string
 }
 
 func Main() {
@@ -84,15 +93,29 @@ func newRootCommand() *cobra.Command {
 				return exitError{code: code}
 			}
 
+			startMetricsServerIfConfigured(opts.MetricsAddr)
+			loadBackendPlugins()
+
+			if opts.StreamFormat != "" {
+				setForcedStreamParser(opts.StreamFormat)
+			}
+
 			exitCode := runWithLoggerAndCleanup(func() int {
 				v, err := config.NewViper(opts.ConfigFile)
 				if err != nil {
 					logError(err.Error())
 					return 1
 				}
+				configureLogHooksFromViper(v)
+
+				outputFormat, err := validateOutputFormat(opts.OutputFormat)
+				if err != nil {
+					logError(err.Error())
+					return 1
+				}
 
 				if opts.Parallel {
-					return runParallelMode(cmd, args, opts, v, name)
+					return runParallelMode(cmd, args, opts, v, name, outputFormat)
 				}
 
 				logInfo("Script started")
@@ -103,7 +126,12 @@ func newRootCommand() *cobra.Command {
 					return 1
 				}
 				logInfo(fmt.Sprintf("Parsed args: mode=%s, task_len=%d, backend=%s", cfg.Mode, len(cfg.Task), cfg.Backend))
-				return runSingleMode(cfg, name)
+
+				fallbackRaw := opts.BackendFallback
+				if !cmd.Flags().Changed("backend-fallback") {
+					fallbackRaw = v.GetString("backend-fallback")
+				}
+				return runSingleMode(cfg, name, outputFormat, parseBackendFallback(fallbackRaw))
 			})
 
 			if exitCode == 0 {
@@ -115,7 +143,8 @@ func newRootCommand() *cobra.Command {
 	cmd.CompletionOptions.DisableDefaultCmd = true
 
 	addRootFlags(cmd.Flags(), opts)
-	cmd.AddCommand(newVersionCommand(name), newCleanupCommand())
+	cmd.AddCommand(newVersionCommand(name), newCleanupCommand(), newCompletionCommand(cmd, name), newReplCommand(name), newCacheCommand())
+	registerCompletions(cmd)
 
 	return cmd
 }
@@ -127,15 +156,22 @@ func addRootFlags(fs *pflag.FlagSet, opts *cliOptions) {
 
 	fs.BoolVar(&opts.Parallel, "parallel", false, "Run tasks in parallel (config from stdin)")
 	fs.BoolVar(&opts.FullOutput, "full-output", false, "Parallel mode: include full task output (legacy)")
+	fs.StringVar(&opts.OutputFormat, "output-format", outputFormatText, "Output format: text, json, ndjson")
 
-	fs.StringVar(&opts.Backend, "backend", defaultBackendName, "Backend to use (codex, claude, gemini, opencode)")
+	fs.StringVar(&opts.Backend, "backend", defaultBackendName, "Backend to use (codex, claude, gemini, opencode, or auto)")
+	fs.StringVar(&opts.BackendFallback, "backend-fallback", "", "Comma-separated backend fallback order, e.g. codex,claude,gemini")
 	fs.StringVar(&opts.Model, "model", "", "Model override")
 	fs.StringVar(&opts.ReasoningEffort, "reasoning-effort", "", "Reasoning effort (backend-specific)")
 	fs.StringVar(&opts.Agent, "agent", "", "Agent preset name (from ~/.codeagent/models.json)")
 	fs.StringVar(&opts.PromptFile, "prompt-file", "", "Prompt file path")
+	fs.StringVar(&opts.Profile, "profile", "", "Config profile name (from the profiles table in the config file)")
 
 	fs.BoolVar(&opts.SkipPermissions, "skip-permissions", false, "Skip permissions prompts (also via CODEAGENT_SKIP_PERMISSIONS)")
 	fs.BoolVar(&opts.SkipPermissions, "dangerously-skip-permissions", false, "Alias for --skip-permissions")
+
+	fs.StringVar(&opts.MetricsAddr, "metrics-addr", "", "Bind address for a Prometheus /metrics endpoint (e.g. :9090); disabled when empty")
+
+	fs.StringVar(&opts.StreamFormat, "stream-format", "", "Force JSON stream parsing to a single named parser (openai, ndjson, codex, claude, gemini, opencode); auto-detected when empty")
 }
 
 func newVersionCommand(name string) *cobra.Command {
@@ -234,40 +270,58 @@ func buildSingleConfig(cmd *cobra.Command, args []string, rawArgv []string, opts
 	promptFileExplicit := false
 	yolo := false
 
-	if cmd.Flags().Changed("agent") {
+	profileName := opts.Profile
+	if !cmd.Flags().Changed("profile") {
+		profileName = v.GetString("profile")
+	}
+	profile, err := config.ResolveProfile(v, profileName)
+	if err != nil {
+		return nil, fmt.Errorf("--profile flag invalid value: %w", err)
+	}
+
+	switch {
+	case cmd.Flags().Changed("agent"):
 		agentName = strings.TrimSpace(opts.Agent)
 		if agentName == "" {
 			return nil, fmt.Errorf("--agent flag requires a value")
 		}
+	case config.EnvOverride("agent") != "":
+		agentName = config.EnvOverride("agent")
+	case profile != nil && profile.Agent != "":
+		agentName = profile.Agent
+	default:
+		agentName = strings.TrimSpace(v.GetString("agent"))
+	}
+	if agentName != "" {
 		if err := config.ValidateAgentName(agentName); err != nil {
 			return nil, fmt.Errorf("--agent flag invalid value: %w", err)
 		}
-	} else {
-		agentName = strings.TrimSpace(v.GetString("agent"))
-		if agentName != "" {
-			if err := config.ValidateAgentName(agentName); err != nil {
-				return nil, fmt.Errorf("--agent flag invalid value: %w", err)
-			}
-		}
 	}
 
 	var resolvedBackend, resolvedModel, resolvedPromptFile, resolvedReasoning string
 	if agentName != "" {
 		var resolvedYolo bool
-		resolvedBackend, resolvedModel, resolvedPromptFile, resolvedReasoning, _, _, resolvedYolo = config.ResolveAgentConfig(agentName)
+		resolvedBackend, resolvedModel, resolvedPromptFile, resolvedReasoning, _, _, resolvedYolo, _ = config.ResolveAgentConfig(agentName)
 		yolo = resolvedYolo
 	}
 
-	if cmd.Flags().Changed("prompt-file") {
+	switch {
+	case cmd.Flags().Changed("prompt-file"):
 		promptFile = strings.TrimSpace(opts.PromptFile)
 		if promptFile == "" {
 			return nil, fmt.Errorf("--prompt-file flag requires a value")
 		}
 		promptFileExplicit = true
-	} else if val := strings.TrimSpace(v.GetString("prompt-file")); val != "" {
-		promptFile = val
+	case config.EnvOverride("prompt-file") != "":
+		promptFile = config.EnvOverride("prompt-file")
 		promptFileExplicit = true
-	} else {
+	case profile != nil && profile.PromptFile != "":
+		promptFile = profile.PromptFile
+		promptFileExplicit = true
+	case strings.TrimSpace(v.GetString("prompt-file")) != "":
+		promptFile = strings.TrimSpace(v.GetString("prompt-file"))
+		promptFileExplicit = true
+	default:
 		promptFile = resolvedPromptFile
 	}
 
@@ -283,12 +337,16 @@ func buildSingleConfig(cmd *cobra.Command, args []string, rawArgv []string, opts
 	switch {
 	case agentFlagChanged && backendFlagChanged && lastFlagIndex(rawArgv, "agent") > lastFlagIndex(rawArgv, "backend"):
 		backendName = resolvedBackend
-	case !backendFlagChanged && agentName != "":
+	case backendFlagChanged:
+		// already set above
+	case config.EnvOverride("backend") != "":
+		backendName = config.EnvOverride("backend")
+	case profile != nil && profile.Backend != "":
+		backendName = profile.Backend
+	case strings.TrimSpace(v.GetString("backend")) != "":
+		backendName = strings.TrimSpace(v.GetString("backend"))
+	case agentName != "":
 		backendName = resolvedBackend
-	case !backendFlagChanged:
-		if val := strings.TrimSpace(v.GetString("backend")); val != "" {
-			backendName = val
-		}
 	}
 
 	modelFlagChanged := cmd.Flags().Changed("model")
@@ -302,28 +360,44 @@ func buildSingleConfig(cmd *cobra.Command, args []string, rawArgv []string, opts
 	switch {
 	case agentFlagChanged && modelFlagChanged && lastFlagIndex(rawArgv, "agent") > lastFlagIndex(rawArgv, "model"):
 		model = strings.TrimSpace(resolvedModel)
-	case !modelFlagChanged && agentName != "":
-		model = strings.TrimSpace(resolvedModel)
-	case !modelFlagChanged:
+	case modelFlagChanged:
+		// already set above
+	case config.EnvOverride("model") != "":
+		model = config.EnvOverride("model")
+	case profile != nil && profile.Model != "":
+		model = profile.Model
+	case strings.TrimSpace(v.GetString("model")) != "":
 		model = strings.TrimSpace(v.GetString("model"))
+	case agentName != "":
+		model = strings.TrimSpace(resolvedModel)
 	}
 
-	if cmd.Flags().Changed("reasoning-effort") {
+	switch {
+	case cmd.Flags().Changed("reasoning-effort"):
 		reasoningEffort = strings.TrimSpace(opts.ReasoningEffort)
 		if reasoningEffort == "" {
 			return nil, fmt.Errorf("--reasoning-effort flag requires a value")
 		}
-	} else if val := strings.TrimSpace(v.GetString("reasoning-effort")); val != "" {
-		reasoningEffort = val
-	} else if agentName != "" {
+	case config.EnvOverride("reasoning-effort") != "":
+		reasoningEffort = config.EnvOverride("reasoning-effort")
+	case profile != nil && profile.ReasoningEffort != "":
+		reasoningEffort = profile.ReasoningEffort
+	case strings.TrimSpace(v.GetString("reasoning-effort")) != "":
+		reasoningEffort = strings.TrimSpace(v.GetString("reasoning-effort"))
+	case agentName != "":
 		reasoningEffort = strings.TrimSpace(resolvedReasoning)
 	}
 
 	skipChanged := cmd.Flags().Changed("skip-permissions") || cmd.Flags().Changed("dangerously-skip-permissions")
 	skipPermissions := false
-	if skipChanged {
+	switch {
+	case skipChanged:
 		skipPermissions = opts.SkipPermissions
-	} else {
+	case config.EnvOverride("skip-permissions") != "":
+		skipPermissions = config.ParseBoolFlag(config.EnvOverride("skip-permissions"), false)
+	case profile != nil && profile.SkipPermissions:
+		skipPermissions = true
+	default:
 		skipPermissions = v.GetBool("skip-permissions")
 	}
 
@@ -396,7 +470,7 @@ func lastFlagIndex(argv []string, name string) int {
 	return last
 }
 
-func runParallelMode(cmd *cobra.Command, args []string, opts *cliOptions, v *viper.Viper, name string) int {
+func runParallelMode(cmd *cobra.Command, args []string, opts *cliOptions, v *viper.Viper, name string, outputFormat string) int {
 	if len(args) > 0 {
 		fmt.Fprintln(os.Stderr, "ERROR: --parallel reads its task configuration from stdin; no positional arguments are allowed.")
 		fmt.Fprintln(os.Stderr, "Usage examples:")
@@ -447,7 +521,34 @@ func runParallelMode(cmd *cobra.Command, args []string, opts *cliOptions, v *vip
 		skipPermissions = v.GetBool("skip-permissions")
 	}
 
-	backend, err := selectBackendFn(backendName)
+	globalProfileName := opts.Profile
+	if !cmd.Flags().Changed("profile") {
+		globalProfileName = v.GetString("profile")
+	}
+	globalProfile, err := config.ResolveProfile(v, globalProfileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: --profile flag invalid value: %v\n", err)
+		return 1
+	}
+	if globalProfile != nil {
+		if !cmd.Flags().Changed("backend") && globalProfile.Backend != "" {
+			backendName = globalProfile.Backend
+		}
+		if !cmd.Flags().Changed("model") && globalProfile.Model != "" {
+			model = globalProfile.Model
+		}
+		if !skipChanged {
+			skipPermissions = skipPermissions || globalProfile.SkipPermissions
+		}
+	}
+
+	fallbackRaw := opts.BackendFallback
+	if !cmd.Flags().Changed("backend-fallback") {
+		fallbackRaw = v.GetString("backend-fallback")
+	}
+	fallbackOrder := parseBackendFallback(fallbackRaw)
+
+	backend, err := resolveBackendWithFallback(backendName, fallbackOrder)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
 		return 1
@@ -469,13 +570,38 @@ func runParallelMode(cmd *cobra.Command, args []string, opts *cliOptions, v *vip
 	cfg.GlobalBackend = backendName
 	model = strings.TrimSpace(model)
 	for i := range cfg.Tasks {
-		if strings.TrimSpace(cfg.Tasks[i].Backend) == "" {
-			cfg.Tasks[i].Backend = backendName
+		task := &cfg.Tasks[i]
+
+		if taskProfileName := strings.TrimSpace(task.Profile); taskProfileName != "" {
+			taskProfile, err := config.ResolveProfile(v, taskProfileName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: task %q: %v\n", task.ID, err)
+				return 1
+			}
+			if taskProfile != nil {
+				if strings.TrimSpace(task.Backend) == "" && taskProfile.Backend != "" {
+					task.Backend = taskProfile.Backend
+				}
+				if strings.TrimSpace(task.Model) == "" && taskProfile.Model != "" {
+					task.Model = taskProfile.Model
+				}
+				if strings.TrimSpace(task.Agent) == "" && taskProfile.Agent != "" {
+					task.Agent = taskProfile.Agent
+				}
+				if strings.TrimSpace(task.PromptFile) == "" && taskProfile.PromptFile != "" {
+					task.PromptFile = taskProfile.PromptFile
+				}
+				task.SkipPermissions = task.SkipPermissions || taskProfile.SkipPermissions
+			}
 		}
-		if strings.TrimSpace(cfg.Tasks[i].Model) == "" && model != "" {
-			cfg.Tasks[i].Model = model
+
+		if strings.TrimSpace(task.Backend) == "" {
+			task.Backend = backendName
+		}
+		if strings.TrimSpace(task.Model) == "" && model != "" {
+			task.Model = model
 		}
-		cfg.Tasks[i].SkipPermissions = cfg.Tasks[i].SkipPermissions || skipPermissions
+		task.SkipPermissions = task.SkipPermissions || skipPermissions
 	}
 
 	timeoutSec := resolveTimeout()
@@ -485,24 +611,50 @@ func runParallelMode(cmd *cobra.Command, args []string, opts *cliOptions, v *vip
 		return 1
 	}
 
-	results := executeConcurrent(layers, timeoutSec)
-
-	for i := range results {
-		results[i].CoverageTarget = defaultCoverageTarget
-		if results[i].Message == "" {
-			continue
+	var results []TaskResult
+	for _, layer := range layers {
+		layerResults := executeConcurrent([][]TaskSpec{layer}, timeoutSec)
+		for i := range layerResults {
+			enrichTaskResult(&layerResults[i])
+			if outputFormat == outputFormatNDJSON {
+				if err := emitTaskResultNDJSON(os.Stdout, backendName, layerResults[i]); err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR: failed to emit task result: %v\n", err)
+				}
+			}
 		}
-
-		lines := strings.Split(results[i].Message, "\n")
-		results[i].Coverage = extractCoverageFromLines(lines)
-		results[i].CoverageNum = extractCoverageNum(results[i].Coverage)
-		results[i].FilesChanged = extractFilesChangedFromLines(lines)
-		results[i].TestsPassed, results[i].TestsFailed = extractTestResultsFromLines(lines)
-		results[i].KeyOutput = extractKeyOutputFromLines(lines, 150)
+		results = append(results, layerResults...)
+	}
+
+	switch outputFormat {
+	case outputFormatNDJSON:
+		// Already streamed above, nothing more to print.
+	case outputFormatJSON:
+		records := make([]taskResultRecord, 0, len(results))
+		for _, res := range results {
+			records = append(records, taskResultRecord{
+				TaskID:       res.TaskID,
+				Backend:      backendName,
+				ExitCode:     res.ExitCode,
+				Message:      res.Message,
+				SessionID:    res.SessionID,
+				Error:        res.Error,
+				LogPath:      res.LogPath,
+				Coverage:     res.Coverage,
+				FilesChanged: res.FilesChanged,
+				TestsPassed:  res.TestsPassed,
+				TestsFailed:  res.TestsFailed,
+			})
+		}
+		data, err := json.Marshal(records)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: failed to marshal results: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Println(generateFinalOutputWithMode(results, !fullOutput))
 	}
 
-	fmt.Println(generateFinalOutputWithMode(results, !fullOutput))
-
 	exitCode := 0
 	for _, res := range results {
 		if res.ExitCode != 0 {
@@ -512,8 +664,25 @@ func runParallelMode(cmd *cobra.Command, args []string, opts *cliOptions, v *vip
 	return exitCode
 }
 
-func runSingleMode(cfg *Config, name string) int {
-	backend, err := selectBackendFn(cfg.Backend)
+// enrichTaskResult populates the structured report fields (coverage, files
+// changed, test counts) that generateFinalOutputWithMode derives from a
+// task's raw message, so json/ndjson consumers get the same data.
+func enrichTaskResult(result *TaskResult) {
+	result.CoverageTarget = defaultCoverageTarget
+	if result.Message == "" {
+		return
+	}
+
+	lines := strings.Split(result.Message, "\n")
+	result.Coverage = extractCoverageFromLines(lines)
+	result.CoverageNum = extractCoverageNum(result.Coverage)
+	result.FilesChanged = extractFilesChangedFromLines(lines)
+	result.TestsPassed, result.TestsFailed = extractTestResultsFromLines(lines)
+	result.KeyOutput = extractKeyOutputFromLines(lines, 150)
+}
+
+func runSingleMode(cfg *Config, name string, outputFormat string, fallbackOrder []string) int {
+	backend, err := resolveBackendWithFallback(cfg.Backend, fallbackOrder)
 	if err != nil {
 		logError(err.Error())
 		return 1
@@ -643,6 +812,15 @@ func runSingleMode(cfg *Config, name string) int {
 	}
 
 	result := runTaskFn(taskSpec, false, cfg.Timeout)
+	result = retryOnBackendUnavailable(cfg, taskSpec, result, fallbackOrder)
+	enrichTaskResult(&result)
+
+	if outputFormat == outputFormatJSON || outputFormat == outputFormatNDJSON {
+		if err := emitSingleModeResult(os.Stdout, cfg.Backend, codexCommand, codexArgs, logger.Path(), result); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: failed to emit result: %v\n", err)
+		}
+		return result.ExitCode
+	}
 
 	if result.ExitCode != 0 {
 		return result.ExitCode
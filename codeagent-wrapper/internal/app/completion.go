@@ -0,0 +1,171 @@
+package wrapper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	config "codeagent-wrapper/internal/config"
+	ilogger "codeagent-wrapper/internal/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var sessionIDFromLogLine = regexp.MustCompile(`SESSION_ID:\s*(\S+)`)
+
+// newCompletionCommand returns the `completion [bash|zsh|fish|powershell]`
+// subcommand. Cobra's default completion command is disabled on the root
+// command (see newRootCommand), so this is the only way users get
+// tab-completion for --backend, --agent, --model and `resume <session_id>`.
+func newCompletionCommand(root *cobra.Command, name string) *cobra.Command {
+	return &cobra.Command{
+		Use:           "completion [bash|zsh|fish|powershell]",
+		Short:         fmt.Sprintf("Generate the shell completion script for %s", name),
+		Args:          cobra.ExactValidArgs(1),
+		ValidArgs:     []string{"bash", "zsh", "fish", "powershell"},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(os.Stdout)
+			default:
+				return fmt.Errorf("unsupported shell %q", args[0])
+			}
+		},
+	}
+}
+
+// registerCompletions wires dynamic shell-completion functions onto the root
+// command's flags and positional arguments.
+func registerCompletions(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("backend", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeBackendNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("agent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeAgentNames(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("model", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeAgentModels(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return completeStringsWithPrefix([]string{"resume"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+		case 1:
+			if args[0] == "resume" {
+				return completePriorSessionIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+}
+
+func completeStringsWithPrefix(candidates []string, toComplete string) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// completeBackendNames enumerates the backends this wrapper knows how to run.
+func completeBackendNames(toComplete string) []string {
+	return completeStringsWithPrefix([]string{"codex", "claude", "gemini", "opencode"}, toComplete)
+}
+
+// completeAgentNames enumerates the six built-in agent preset names (see
+// agentModelsSnapshot). Agents defined only in ~/.codeagent/models.json or
+// under ~/.codeagent/agents are not offered.
+func completeAgentNames(toComplete string) []string {
+	names := make([]string, 0, len(agentModelsSnapshot()))
+	for name := range agentModelsSnapshot() {
+		names = append(names, name)
+	}
+	return completeStringsWithPrefix(names, toComplete)
+}
+
+// completeAgentModels enumerates the distinct model strings configured for
+// any agent, so `--model` completion suggests values actually in use.
+func completeAgentModels(toComplete string) []string {
+	seen := map[string]bool{}
+	models := make([]string, 0)
+	for _, agent := range agentModelsSnapshot() {
+		model := strings.TrimSpace(agent.Model)
+		if model == "" || seen[model] {
+			continue
+		}
+		seen[model] = true
+		models = append(models, model)
+	}
+	return completeStringsWithPrefix(models, toComplete)
+}
+
+func agentModelsSnapshot() map[string]config.AgentModelConfig {
+	// ResolveAgentConfig merges the user's models.json with the registered
+	// built-ins, but has no "list everything" entry point, so this resolves
+	// a fixed list of the six built-in names only (models.json overrides of
+	// those names do apply). Other models.json agents and dynamic agent
+	// prompt files under ~/.codeagent/agents are not included.
+	names := []string{"oracle", "librarian", "explore", "develop", "frontend-ui-ux-engineer", "document-writer"}
+	out := make(map[string]config.AgentModelConfig, len(names))
+	for _, name := range names {
+		backend, model, promptFile, reasoning, _, _, yolo, _ := config.ResolveAgentConfig(name)
+		out[name] = config.AgentModelConfig{Backend: backend, Model: model, PromptFile: promptFile, Reasoning: reasoning, Yolo: yolo}
+	}
+	return out
+}
+
+// completePriorSessionIDs scans this wrapper's log files for previously
+// printed "SESSION_ID: <id>" markers so `resume <TAB>` offers real sessions.
+// It is best-effort: any I/O error just yields no suggestions.
+func completePriorSessionIDs(toComplete string) []string {
+	pattern := filepath.Join(os.TempDir(), ilogger.PrimaryLogPrefix()+"-*.log")
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	ids := make([]string, 0)
+	for _, path := range matches {
+		ids = append(ids, sessionIDsFromLogFile(path, seen)...)
+	}
+	return completeStringsWithPrefix(ids, toComplete)
+}
+
+func sessionIDsFromLogFile(path string, seen map[string]bool) []string {
+	f, err := os.Open(path) // #nosec G304 -- path comes from a glob over our own log directory, not user input
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := sessionIDFromLogLine.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		id := m[1]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
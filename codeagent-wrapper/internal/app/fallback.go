@@ -0,0 +1,115 @@
+package wrapper
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	backend "codeagent-wrapper/internal/backend"
+)
+
+const backendAuto = "auto"
+
+// parseBackendFallback splits a --backend-fallback value ("codex,claude,gemini")
+// into a trimmed, non-empty name list.
+func parseBackendFallback(raw string) []string {
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func backendCommandAvailable(b Backend) bool {
+	if b == nil {
+		return false
+	}
+	_, err := exec.LookPath(b.Command())
+	return err == nil
+}
+
+// resolveBackendWithFallback selects a backend honoring `--backend auto`
+// (first available backend from fallbackOrder, or backend.DefaultFallbackOrder
+// when fallbackOrder is empty) and, for an explicit backend name, falls back
+// to the next entry in fallbackOrder when the requested backend's command
+// isn't on $PATH. It never returns an error solely because a fallback
+// candidate was unavailable - only when every option, including the
+// originally requested backend, is exhausted.
+func resolveBackendWithFallback(backendName string, fallbackOrder []string) (Backend, error) {
+	order := fallbackOrder
+	if len(order) == 0 {
+		order = backend.DefaultFallbackOrder
+	}
+
+	if strings.EqualFold(strings.TrimSpace(backendName), backendAuto) {
+		return backend.SelectAvailable(order)
+	}
+
+	b, err := selectBackendFn(backendName)
+	if err == nil && backendCommandAvailable(b) {
+		return b, nil
+	}
+
+	for _, name := range order {
+		if strings.EqualFold(name, backendName) {
+			continue
+		}
+		fb, fbErr := selectBackendFn(name)
+		if fbErr != nil || !backendCommandAvailable(fb) {
+			continue
+		}
+		logWarn("Backend " + backendName + " unavailable, falling back to " + fb.Name())
+		return fb, nil
+	}
+
+	// Nothing in the fallback chain is usable either; return the original
+	// resolution (or its error) so the caller reports the real cause.
+	return b, err
+}
+
+// retryOnBackendUnavailable re-runs taskSpec against the next backend in
+// fallbackOrder when result reports ExitCodeBackendUnavailable (e.g. an
+// auth failure or rate limit on the currently selected backend), updating
+// cfg.Backend and the codexCommand/buildCodexArgsFn globals to match. It
+// returns the first result that isn't ExitCodeBackendUnavailable, or the
+// last attempt's result if every fallback candidate also reports it.
+func retryOnBackendUnavailable(cfg *Config, taskSpec TaskSpec, result TaskResult, fallbackOrder []string) TaskResult {
+	if result.ExitCode != exitCodeBackendUnavailable {
+		return result
+	}
+
+	order := fallbackOrder
+	if len(order) == 0 {
+		order = backend.DefaultFallbackOrder
+	}
+
+	tried := map[string]bool{strings.ToLower(strings.TrimSpace(cfg.Backend)): true}
+	for _, name := range order {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if tried[key] {
+			continue
+		}
+		tried[key] = true
+
+		fb, err := selectBackendFn(name)
+		if err != nil || !backendCommandAvailable(fb) {
+			continue
+		}
+
+		logWarn(fmt.Sprintf("Backend %q reported unavailable, retrying task with %q", cfg.Backend, fb.Name()))
+		cfg.Backend = fb.Name()
+		codexCommand = fb.Command()
+		buildCodexArgsFn = fb.BuildArgs
+
+		result = runTaskFn(taskSpec, false, cfg.Timeout)
+		if result.ExitCode != exitCodeBackendUnavailable {
+			return result
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,68 @@
+package wrapper
+
+import (
+	"strings"
+
+	ilogger "codeagent-wrapper/internal/logger"
+
+	"github.com/spf13/viper"
+)
+
+// configureLogHooksFromViper enables the built-in logger.Hook sinks named in
+// the "log-hooks" config key (env CODEAGENT_LOG_HOOKS; config file
+// "log-hooks"), a comma-separated list drawn from: syslog, journald,
+// jsonfile, http. jsonfile and http additionally read "log-hooks-jsonfile-path"
+// and "log-hooks-http-url". A hook that fails to initialize (e.g. syslog on
+// a platform without a syslog daemon) only logs a warning; it never fails
+// the command.
+func configureLogHooksFromViper(v *viper.Viper) {
+	raw := strings.TrimSpace(v.GetString("log-hooks"))
+	if raw == "" {
+		return
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		switch name {
+		case "syslog":
+			hook, err := ilogger.NewSyslogHook()
+			if err != nil {
+				logWarn("log-hooks: syslog: " + err.Error())
+				continue
+			}
+			ilogger.AddHook(hook)
+		case "journald":
+			hook, err := ilogger.NewJournaldHook()
+			if err != nil {
+				logWarn("log-hooks: journald: " + err.Error())
+				continue
+			}
+			ilogger.AddHook(hook)
+		case "jsonfile":
+			path := strings.TrimSpace(v.GetString("log-hooks-jsonfile-path"))
+			if path == "" {
+				logWarn("log-hooks: jsonfile requires log-hooks-jsonfile-path")
+				continue
+			}
+			hook, err := ilogger.NewJSONFileHook(path)
+			if err != nil {
+				logWarn("log-hooks: jsonfile: " + err.Error())
+				continue
+			}
+			ilogger.AddHook(hook)
+		case "http":
+			url := strings.TrimSpace(v.GetString("log-hooks-http-url"))
+			if url == "" {
+				logWarn("log-hooks: http requires log-hooks-http-url")
+				continue
+			}
+			ilogger.AddHook(ilogger.NewHTTPHook(url))
+		default:
+			logWarn("log-hooks: unknown hook " + name)
+		}
+	}
+}
@@ -4,11 +4,34 @@ import ilogger "codeagent-wrapper/internal/logger"
 
 type Logger = ilogger.Logger
 type CleanupStats = ilogger.CleanupStats
+type Format = ilogger.Format
+type Field = ilogger.Field
 
-func NewLogger() (*Logger, error) { return ilogger.NewLogger() }
+const FormatText = ilogger.FormatText
+const FormatJSON = ilogger.FormatJSON
+
+func NewLogger(opts ...ilogger.LoggerOption) (*Logger, error) { return ilogger.NewLogger(opts...) }
 
 func NewLoggerWithSuffix(suffix string) (*Logger, error) { return ilogger.NewLoggerWithSuffix(suffix) }
 
+func NewLoggerWithFormat(format Format) (*Logger, error) { return ilogger.NewLoggerWithFormat(format) }
+
+type QueuePolicy = ilogger.QueuePolicy
+
+const (
+	PolicyBlock      = ilogger.PolicyBlock
+	PolicyDropOldest = ilogger.PolicyDropOldest
+	PolicyDropNewest = ilogger.PolicyDropNewest
+)
+
+func WithQueuePolicy(policy QueuePolicy, capacity int) ilogger.LoggerOption {
+	return ilogger.WithQueuePolicy(policy, capacity)
+}
+
+func String(key, value string) Field { return ilogger.String(key, value) }
+
+func Int(key string, value int) Field { return ilogger.Int(key, value) }
+
 func setLogger(l *Logger) { ilogger.SetLogger(l) }
 
 func closeLogger() error { return ilogger.CloseLogger() }
@@ -0,0 +1,27 @@
+package wrapper
+
+import (
+	"strings"
+
+	metrics "codeagent-wrapper/internal/executor/metrics"
+)
+
+// startMetricsServerIfConfigured installs a PrometheusSink and serves it on
+// addr when addr is non-empty (--metrics-addr). The default empty addr
+// leaves the no-op metrics.Sink active, so metrics collection costs nothing
+// unless explicitly enabled.
+func startMetricsServerIfConfigured(addr string) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return
+	}
+
+	sink := metrics.NewPrometheusSink()
+	metrics.SetSink(sink)
+
+	go func() {
+		if err := metrics.Serve(addr, sink); err != nil {
+			logWarn("metrics server stopped: " + err.Error())
+		}
+	}()
+}
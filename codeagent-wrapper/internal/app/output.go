@@ -0,0 +1,108 @@
+package wrapper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Recognized --output-format values. text is the historical human-readable
+// behavior; json/ndjson are for driving the wrapper from other programs.
+const (
+	outputFormatText   = "text"
+	outputFormatJSON   = "json"
+	outputFormatNDJSON = "ndjson"
+)
+
+func validateOutputFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = outputFormatText
+	}
+	switch format {
+	case outputFormatText, outputFormatJSON, outputFormatNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("--output-format must be one of text, json, ndjson (got %q)", format)
+	}
+}
+
+// singleModeResult is the structured record emitted for --output-format=json
+// in single-task mode.
+type singleModeResult struct {
+	Backend      string   `json:"backend"`
+	Command      string   `json:"command"`
+	Args         []string `json:"args"`
+	PID          int      `json:"pid"`
+	LogPath      string   `json:"log_path"`
+	SessionID    string   `json:"session_id,omitempty"`
+	ExitCode     int      `json:"exit_code"`
+	Message      string   `json:"message"`
+	Coverage     string   `json:"coverage,omitempty"`
+	FilesChanged []string `json:"files_changed,omitempty"`
+	TestsPassed  int      `json:"tests_passed,omitempty"`
+	TestsFailed  int      `json:"tests_failed,omitempty"`
+}
+
+func emitSingleModeResult(w *os.File, backendName, command string, args []string, logPath string, result TaskResult) error {
+	record := singleModeResult{
+		Backend:      backendName,
+		Command:      command,
+		Args:         args,
+		PID:          os.Getpid(),
+		LogPath:      logPath,
+		SessionID:    result.SessionID,
+		ExitCode:     result.ExitCode,
+		Message:      result.Message,
+		Coverage:     result.Coverage,
+		FilesChanged: result.FilesChanged,
+		TestsPassed:  result.TestsPassed,
+		TestsFailed:  result.TestsFailed,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// taskResultRecord is the per-task record streamed to stdout, one line per
+// completed task, when --output-format=ndjson is used with --parallel.
+type taskResultRecord struct {
+	TaskID       string   `json:"task_id"`
+	Backend      string   `json:"backend,omitempty"`
+	ExitCode     int      `json:"exit_code"`
+	Message      string   `json:"message"`
+	SessionID    string   `json:"session_id,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	LogPath      string   `json:"log_path,omitempty"`
+	Coverage     string   `json:"coverage,omitempty"`
+	FilesChanged []string `json:"files_changed,omitempty"`
+	TestsPassed  int      `json:"tests_passed,omitempty"`
+	TestsFailed  int      `json:"tests_failed,omitempty"`
+}
+
+func emitTaskResultNDJSON(w *os.File, backendName string, result TaskResult) error {
+	record := taskResultRecord{
+		TaskID:       result.TaskID,
+		Backend:      backendName,
+		ExitCode:     result.ExitCode,
+		Message:      result.Message,
+		SessionID:    result.SessionID,
+		Error:        result.Error,
+		LogPath:      result.LogPath,
+		Coverage:     result.Coverage,
+		FilesChanged: result.FilesChanged,
+		TestsPassed:  result.TestsPassed,
+		TestsFailed:  result.TestsFailed,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
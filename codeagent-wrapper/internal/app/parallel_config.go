@@ -5,5 +5,5 @@ import (
 )
 
 func parseParallelConfig(data []byte) (*ParallelConfig, error) {
-	return executor.ParseParallelConfig(data)
+	return executor.ParseParallelConfigAuto(data)
 }
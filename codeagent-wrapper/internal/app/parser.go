@@ -25,6 +25,14 @@ func parseJSONStreamInternal(r io.Reader, warnFn func(string), infoFn func(strin
 	return parser.ParseJSONStreamInternal(r, warnFn, infoFn, onMessage, onComplete)
 }
 
+func parseJSONStreamTokenized(r io.Reader, warnFn func(string), infoFn func(string), onMessage func(), onComplete func()) (message, threadID string) {
+	return parser.ParseJSONStreamTokenized(r, warnFn, infoFn, onMessage, onComplete)
+}
+
+func parseJSONStreamWithMetrics(r io.Reader, backendName string, warnFn func(string), infoFn func(string), onMessage func(), onComplete func()) (message, threadID string) {
+	return parser.ParseJSONStreamWithMetrics(r, backendName, warnFn, infoFn, onMessage, onComplete)
+}
+
 func hasKey(m map[string]json.RawMessage, key string) bool { return parser.HasKey(m, key) }
 
 func discardInvalidJSON(decoder *json.Decoder, reader *bufio.Reader) (*bufio.Reader, error) {
@@ -32,3 +40,5 @@ func discardInvalidJSON(decoder *json.Decoder, reader *bufio.Reader) (*bufio.Rea
 }
 
 func normalizeText(text interface{}) string { return parser.NormalizeText(text) }
+
+func setForcedStreamParser(name string) (restore func()) { return parser.SetForcedStreamParser(name) }
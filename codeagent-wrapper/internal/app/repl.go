@@ -0,0 +1,275 @@
+package wrapper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	config "codeagent-wrapper/internal/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newReplCommand returns the `repl` subcommand: an interactive loop that
+// submits each line as a task and automatically resumes the session
+// returned by the previous turn, so the user never has to copy-paste a
+// session ID between turns.
+func newReplCommand(name string) *cobra.Command {
+	opts := &cliOptions{}
+
+	cmd := &cobra.Command{
+		Use:           "repl",
+		Short:         "Start an interactive session that reuses session IDs across turns",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := config.NewViper(opts.ConfigFile)
+			if err != nil {
+				return err
+			}
+			cfg, err := buildReplConfig(cmd, opts, v)
+			if err != nil {
+				return err
+			}
+			return runRepl(cfg, name)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.ConfigFile, "config", "", "Config file path (default: $HOME/.codeagent/config.*)")
+	fs.StringVar(&opts.Backend, "backend", defaultBackendName, "Backend to use (codex, claude, gemini, opencode)")
+	fs.StringVar(&opts.Model, "model", "", "Model override")
+	fs.StringVar(&opts.Agent, "agent", "", "Agent preset name (from ~/.codeagent/models.json)")
+	fs.StringVar(&opts.Profile, "profile", "", "Config profile name")
+	fs.BoolVar(&opts.SkipPermissions, "skip-permissions", false, "Skip permissions prompts")
+
+	return cmd
+}
+
+// buildReplConfig resolves the REPL's starting Config the same way
+// buildSingleConfig does for a one-shot invocation, minus the
+// positional-argument handling that doesn't apply to a loop.
+func buildReplConfig(cmd *cobra.Command, opts *cliOptions, v *viper.Viper) (*Config, error) {
+	profileName := opts.Profile
+	if !cmd.Flags().Changed("profile") {
+		profileName = v.GetString("profile")
+	}
+	profile, err := config.ResolveProfile(v, profileName)
+	if err != nil {
+		return nil, fmt.Errorf("--profile flag invalid value: %w", err)
+	}
+
+	agentName := strings.TrimSpace(opts.Agent)
+	if !cmd.Flags().Changed("agent") {
+		switch {
+		case config.EnvOverride("agent") != "":
+			agentName = config.EnvOverride("agent")
+		case profile != nil && profile.Agent != "":
+			agentName = profile.Agent
+		default:
+			agentName = strings.TrimSpace(v.GetString("agent"))
+		}
+	}
+	if agentName != "" {
+		if err := config.ValidateAgentName(agentName); err != nil {
+			return nil, fmt.Errorf("--agent flag invalid value: %w", err)
+		}
+	}
+
+	var resolvedBackend, resolvedModel, resolvedPromptFile, resolvedReasoning string
+	var resolvedYolo bool
+	if agentName != "" {
+		resolvedBackend, resolvedModel, resolvedPromptFile, resolvedReasoning, _, _, resolvedYolo, _ = config.ResolveAgentConfig(agentName)
+	}
+
+	backendName := opts.Backend
+	if !cmd.Flags().Changed("backend") {
+		switch {
+		case config.EnvOverride("backend") != "":
+			backendName = config.EnvOverride("backend")
+		case profile != nil && profile.Backend != "":
+			backendName = profile.Backend
+		case strings.TrimSpace(v.GetString("backend")) != "":
+			backendName = strings.TrimSpace(v.GetString("backend"))
+		case agentName != "":
+			backendName = resolvedBackend
+		default:
+			backendName = defaultBackendName
+		}
+	}
+
+	model := opts.Model
+	if !cmd.Flags().Changed("model") {
+		switch {
+		case config.EnvOverride("model") != "":
+			model = config.EnvOverride("model")
+		case profile != nil && profile.Model != "":
+			model = profile.Model
+		case strings.TrimSpace(v.GetString("model")) != "":
+			model = strings.TrimSpace(v.GetString("model"))
+		case agentName != "":
+			model = resolvedModel
+		}
+	}
+
+	skipPermissions := opts.SkipPermissions
+	if !cmd.Flags().Changed("skip-permissions") {
+		switch {
+		case config.EnvOverride("skip-permissions") != "":
+			skipPermissions = config.ParseBoolFlag(config.EnvOverride("skip-permissions"), false)
+		case profile != nil && profile.SkipPermissions:
+			skipPermissions = true
+		default:
+			skipPermissions = v.GetBool("skip-permissions")
+		}
+	}
+
+	return &Config{
+		WorkDir:         defaultWorkdir,
+		Backend:         backendName,
+		Agent:           agentName,
+		PromptFile:      resolvedPromptFile,
+		SkipPermissions: skipPermissions,
+		Yolo:            resolvedYolo,
+		Model:           strings.TrimSpace(model),
+		ReasoningEffort: strings.TrimSpace(resolvedReasoning),
+	}, nil
+}
+
+// runRepl drives the read-eval-print loop itself: one task per line, slash
+// commands to mutate cfg between turns, and automatic session resumption.
+func runRepl(cfg *Config, name string) error {
+	fmt.Printf("%s repl - backend=%s model=%s (/exit to quit, /save <file> to write the transcript)\n", name, cfg.Backend, cfg.Model)
+
+	var transcript strings.Builder
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			exit, err := handleReplCommand(line, cfg, &transcript)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			}
+			if exit {
+				break
+			}
+			continue
+		}
+
+		taskSpec := TaskSpec{
+			Task:            line,
+			WorkDir:         cfg.WorkDir,
+			Model:           cfg.Model,
+			ReasoningEffort: cfg.ReasoningEffort,
+			Agent:           cfg.Agent,
+			SkipPermissions: cfg.SkipPermissions,
+		}
+		if cfg.SessionID != "" {
+			taskSpec.Mode = "resume"
+			taskSpec.SessionID = cfg.SessionID
+		} else {
+			taskSpec.Mode = "new"
+		}
+
+		backend, err := selectBackendFn(cfg.Backend)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			continue
+		}
+		cfg.Backend = backend.Name()
+
+		result := runTaskFn(taskSpec, false, resolveTimeout())
+		fmt.Println(result.Message)
+		transcript.WriteString(fmt.Sprintf("### You\n%s\n\n### %s\n%s\n\n", line, cfg.Backend, result.Message))
+		if result.SessionID != "" {
+			cfg.SessionID = result.SessionID
+		}
+	}
+
+	return scanner.Err()
+}
+
+// handleReplCommand applies one slash command to cfg. exit reports whether
+// the REPL should stop (/exit).
+func handleReplCommand(line string, cfg *Config, transcript *strings.Builder) (exit bool, err error) {
+	fields := strings.SplitN(line, " ", 2)
+	name := fields[0]
+	arg := ""
+	if len(fields) > 1 {
+		arg = strings.TrimSpace(fields[1])
+	}
+
+	switch name {
+	case "/exit", "/quit":
+		return true, nil
+
+	case "/backend":
+		if arg == "" {
+			return false, fmt.Errorf("/backend requires a value")
+		}
+		cfg.Backend = arg
+		return false, nil
+
+	case "/model":
+		if arg == "" {
+			return false, fmt.Errorf("/model requires a value")
+		}
+		cfg.Model = arg
+		return false, nil
+
+	case "/agent":
+		if arg == "" {
+			return false, fmt.Errorf("/agent requires a value")
+		}
+		if err := config.ValidateAgentName(arg); err != nil {
+			return false, err
+		}
+		backend, model, promptFile, reasoning, _, _, yolo, _ := config.ResolveAgentConfig(arg)
+		cfg.Agent = arg
+		cfg.Backend = backend
+		if model != "" {
+			cfg.Model = model
+		}
+		cfg.PromptFile = promptFile
+		cfg.ReasoningEffort = reasoning
+		cfg.Yolo = yolo
+		return false, nil
+
+	case "/workdir":
+		if arg == "" {
+			return false, fmt.Errorf("/workdir requires a value")
+		}
+		cfg.WorkDir = arg
+		return false, nil
+
+	case "/reset":
+		cfg.SessionID = ""
+		fmt.Println("session reset; the next turn starts a new conversation")
+		return false, nil
+
+	case "/save":
+		if arg == "" {
+			return false, fmt.Errorf("/save requires a file path")
+		}
+		if err := os.WriteFile(arg, []byte(transcript.String()), 0o644); err != nil {
+			return false, err
+		}
+		fmt.Printf("transcript saved to %s\n", arg)
+		return false, nil
+
+	default:
+		return false, fmt.Errorf("unknown command %q (try /backend, /model, /agent, /workdir, /reset, /save, /exit)", name)
+	}
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FailureClass categorizes why a backend invocation failed, driving retry
+// policy decisions in the executor package's RetryPolicy handling.
+type FailureClass int
+
+const (
+	// FailureUnknown means no recognized pattern matched; callers typically
+	// treat this as retryable by default.
+	FailureUnknown FailureClass = iota
+	// FailureRateLimited means the backend reported a rate limit or quota
+	// error - retryable, usually with backoff.
+	FailureRateLimited
+	// FailureAuth means the backend reported an authentication/authorization
+	// failure - retryable only if credentials might change out-of-band.
+	FailureAuth
+	// FailureNonRetryable means the failure is inherent to the request
+	// itself (e.g. the prompt exceeded the model's context window) and
+	// retrying with the same input cannot succeed.
+	FailureNonRetryable
+)
+
+// Classifier is implemented by backends that need bespoke failure
+// recognition beyond the shared patterns in ClassifyFailure. Backends that
+// don't implement it (or whose ClassifyFailure returns FailureUnknown) fall
+// back to the shared heuristics.
+type Classifier interface {
+	ClassifyFailure(message string) FailureClass
+}
+
+var sharedNonRetryablePatterns = []string{
+	"*context length exceeded*",
+	"*maximum context length*",
+	"*context_length_exceeded*",
+	"*prompt is too long*",
+}
+
+var sharedAuthPatterns = []string{
+	"*unauthorized*",
+	"*authentication failed*",
+	"*invalid api key*",
+	"*401*",
+	"*403 forbidden*",
+}
+
+var sharedRateLimitPatterns = []string{
+	"*429*",
+	"*rate limit*",
+	"*rate-limit*",
+	"*quota exceeded*",
+	"*too many requests*",
+	"*resource_exhausted*",
+}
+
+// ClassifyFailure classifies message (typically a task's combined
+// stderr/error text) for backendName, preferring that backend's own
+// Classifier when it recognizes the message before falling back to the
+// patterns shared across all backends.
+func ClassifyFailure(backendName, message string) FailureClass {
+	if strings.TrimSpace(message) == "" {
+		return FailureUnknown
+	}
+
+	if b, err := Select(backendName); err == nil {
+		if c, ok := b.(Classifier); ok {
+			if class := c.ClassifyFailure(message); class != FailureUnknown {
+				return class
+			}
+		}
+	}
+
+	switch {
+	case MatchesAnyGlob(message, sharedNonRetryablePatterns):
+		return FailureNonRetryable
+	case MatchesAnyGlob(message, sharedAuthPatterns):
+		return FailureAuth
+	case MatchesAnyGlob(message, sharedRateLimitPatterns):
+		return FailureRateLimited
+	default:
+		return FailureUnknown
+	}
+}
+
+// MatchGlob reports whether s matches pattern, a case-insensitive glob where
+// "*" matches any run of characters (including none). This is intentionally
+// simpler than filepath.Match: callers match against free-form log/error
+// text, not filesystem paths, so "*" must be allowed to cross what would be
+// path separators.
+func MatchGlob(pattern, s string) bool {
+	return globRegexp(pattern).MatchString(strings.ToLower(s))
+}
+
+// MatchesAnyGlob reports whether s matches any of patterns (see MatchGlob).
+func MatchesAnyGlob(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	globRegexpMu    sync.RWMutex
+	globRegexpCache = map[string]*regexp.Regexp{}
+)
+
+func globRegexp(pattern string) *regexp.Regexp {
+	pattern = strings.ToLower(pattern)
+
+	globRegexpMu.RLock()
+	re, ok := globRegexpCache[pattern]
+	globRegexpMu.RUnlock()
+	if ok {
+		return re
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		if r == '*' {
+			b.WriteString(".*")
+		} else {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	re = regexp.MustCompile(b.String())
+
+	globRegexpMu.Lock()
+	globRegexpCache[pattern] = re
+	globRegexpMu.Unlock()
+	return re
+}
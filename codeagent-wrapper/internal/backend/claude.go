@@ -14,6 +14,19 @@ type ClaudeBackend struct{}
 
 func (ClaudeBackend) Name() string    { return "claude" }
 func (ClaudeBackend) Command() string { return "claude" }
+
+// ClassifyFailure recognizes Claude-specific failure wording not covered by
+// the shared patterns in ClassifyFailure.
+func (ClaudeBackend) ClassifyFailure(message string) FailureClass {
+	switch {
+	case MatchesAnyGlob(message, []string{"*usage_limit_reached*", "*overloaded_error*"}):
+		return FailureRateLimited
+	case MatchesAnyGlob(message, []string{"*authentication_error*", "*invalid x-api-key*"}):
+		return FailureAuth
+	default:
+		return FailureUnknown
+	}
+}
 func (ClaudeBackend) Env(baseURL, apiKey string) map[string]string {
 	baseURL = strings.TrimSpace(baseURL)
 	apiKey = strings.TrimSpace(apiKey)
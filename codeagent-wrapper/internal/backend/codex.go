@@ -10,6 +10,19 @@ type CodexBackend struct{}
 
 func (CodexBackend) Name() string    { return "codex" }
 func (CodexBackend) Command() string { return "codex" }
+
+// ClassifyFailure recognizes Codex-specific failure wording not covered by
+// the shared patterns in ClassifyFailure.
+func (CodexBackend) ClassifyFailure(message string) FailureClass {
+	switch {
+	case MatchesAnyGlob(message, []string{"*insufficient_quota*", "*usage limit reached*"}):
+		return FailureRateLimited
+	case MatchesAnyGlob(message, []string{"*invalid_api_key*", "*incorrect api key*"}):
+		return FailureAuth
+	default:
+		return FailureUnknown
+	}
+}
 func (CodexBackend) Env(baseURL, apiKey string) map[string]string {
 	baseURL = strings.TrimSpace(baseURL)
 	apiKey = strings.TrimSpace(apiKey)
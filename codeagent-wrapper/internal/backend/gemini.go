@@ -12,6 +12,19 @@ type GeminiBackend struct{}
 
 func (GeminiBackend) Name() string    { return "gemini" }
 func (GeminiBackend) Command() string { return "gemini" }
+
+// ClassifyFailure recognizes Gemini-specific failure wording not covered by
+// the shared patterns in ClassifyFailure.
+func (GeminiBackend) ClassifyFailure(message string) FailureClass {
+	switch {
+	case MatchesAnyGlob(message, []string{"*resource_exhausted*", "*quota_exceeded*"}):
+		return FailureRateLimited
+	case MatchesAnyGlob(message, []string{"*permission_denied*", "*unauthenticated*"}):
+		return FailureAuth
+	default:
+		return FailureUnknown
+	}
+}
 func (GeminiBackend) Env(baseURL, apiKey string) map[string]string {
 	baseURL = strings.TrimSpace(baseURL)
 	apiKey = strings.TrimSpace(apiKey)
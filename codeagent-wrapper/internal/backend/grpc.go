@@ -0,0 +1,206 @@
+package backend
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	config "codeagent-wrapper/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// ProtocolBackend is a resolved backend endpoint capable of producing a client
+// for dispatching prompts, independent of the wire transport (HTTP today,
+// gRPC for self-hosted inference servers like llama.cpp or vLLM).
+type ProtocolBackend interface {
+	NewClient(ctx context.Context) (BackendClient, error)
+}
+
+// BackendClient dispatches a single prompt and streams back reply chunks.
+type BackendClient interface {
+	// Predict sends req and returns a channel of streamed reply chunks. The
+	// channel is closed once the server finishes the turn or ctx is done.
+	Predict(ctx context.Context, req *PredictRequest) (<-chan *PredictReply, error)
+	Close() error
+}
+
+// PredictRequest mirrors the fields a `codeagent` invocation carries today,
+// so a self-hosted gRPC backend can be driven the same way a CLI subprocess is.
+//
+// The canonical wire contract is:
+//
+//	service Predict {
+//	  rpc Predict(PredictRequest) returns (stream PredictReply);
+//	}
+//
+// These Go structs stand in for the generated protobuf types; a real
+// deployment should replace them with the output of `protoc` against that
+// .proto definition and wire it into grpcClient below.
+type PredictRequest struct {
+	Model           string
+	Prompt          string
+	ReasoningEffort string
+	Yolo            bool
+}
+
+// PredictReply is one streamed chunk of a Predict response.
+type PredictReply struct {
+	Text       string
+	Done       bool
+	SessionID  string
+	ErrMessage string
+}
+
+// ResolveProtocolBackend looks up the resolved config.BackendConfig for
+// backendName and returns the ProtocolBackend implementation matching its
+// protocol. HTTP-protocol backends (the default, used by defaultModelsConfig)
+// have no direct client here - subprocess backends talk HTTP through the
+// wrapped CLI instead - so only "grpc" backends produce a non-nil value.
+//
+// NOT YET WIRED INTO DISPATCH: Select/SelectAvailable, which
+// internal/executor actually calls to run a task, only know about the
+// subprocess-based Backend interface (Command/BuildArgs/Env) and never
+// consult this function. Declaring protocol: "grpc" in models.json passes
+// config validation and lets a caller build a working BackendClient by hand
+// (see grpcClient.Predict), but no prompt is dispatched over gRPC end to end
+// yet - that requires threading a streaming-response code path through
+// internal/executor alongside today's subprocess-stdout-parsing one, which
+// is a larger follow-up than this type alone.
+func ResolveProtocolBackend(backendName string) (ProtocolBackend, error) {
+	cfg := config.ResolveBackendConfigFull(backendName)
+	switch cfg.NormalizedProtocol() {
+	case config.ProtocolGRPC:
+		if strings.TrimSpace(cfg.GRPCTarget) == "" {
+			return nil, fmt.Errorf("backend %q declares protocol=grpc but has no grpc_target", backendName)
+		}
+		return &grpcBackend{name: backendName, cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("backend %q uses protocol %q, which has no direct client", backendName, cfg.NormalizedProtocol())
+	}
+}
+
+// grpcConnPool caches one *grpc.ClientConn per normalized backend key so
+// repeated task invocations (e.g. parallel mode) reuse the same connection.
+var grpcConnPool sync.Map // map[string]*grpc.ClientConn
+
+type grpcBackend struct {
+	name string
+	cfg  config.BackendConfig
+}
+
+func (g *grpcBackend) NewClient(ctx context.Context) (BackendClient, error) {
+	conn, err := dialGRPC(g.name, g.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcClient{conn: conn, cfg: g.cfg}, nil
+}
+
+func dialGRPC(backendName string, cfg config.BackendConfig) (*grpc.ClientConn, error) {
+	key := strings.ToLower(strings.TrimSpace(backendName))
+	if conn, ok := grpcConnPool.Load(key); ok {
+		return conn.(*grpc.ClientConn), nil
+	}
+
+	creds, err := grpcTransportCreds(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(cfg.GRPCTarget, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("dial grpc backend %q at %s: %w", backendName, cfg.GRPCTarget, err)
+	}
+
+	actual, loaded := grpcConnPool.LoadOrStore(key, conn)
+	if loaded {
+		_ = conn.Close()
+		return actual.(*grpc.ClientConn), nil
+	}
+	return conn, nil
+}
+
+func grpcTransportCreds(cfg config.BackendConfig) (credentials.TransportCredentials, error) {
+	if cfg.GRPCInsecure {
+		return insecure.NewCredentials(), nil
+	}
+	if strings.TrimSpace(cfg.GRPCTLSCertPath) == "" {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+	}
+
+	pem, err := os.ReadFile(cfg.GRPCTLSCertPath) // #nosec G304 -- path comes from user-owned models.json
+	if err != nil {
+		return nil, fmt.Errorf("read grpc tls cert %s: %w", cfg.GRPCTLSCertPath, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("grpc tls cert %s contains no usable certificates", cfg.GRPCTLSCertPath)
+	}
+	return credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}), nil
+}
+
+// jsonCodec implements grpc's encoding.Codec interface over encoding/json,
+// so PredictRequest/PredictReply - plain Go structs standing in for real
+// generated protobuf types (see PredictRequest's doc comment) - can be sent
+// with SendMsg/RecvMsg. grpc-go's default codec type-asserts its argument to
+// proto.Message and fails otherwise; passed via grpc.ForceCodec per call (not
+// encoding.RegisterCodec'd globally) so it doesn't affect any other RPC that
+// might share this *grpc.ClientConn.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "json" }
+
+type grpcClient struct {
+	conn *grpc.ClientConn
+	cfg  config.BackendConfig
+}
+
+func (c *grpcClient) Predict(ctx context.Context, req *PredictRequest) (<-chan *PredictReply, error) {
+	if len(c.cfg.GRPCAuthMeta) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(c.cfg.GRPCAuthMeta))
+	}
+
+	stream, err := c.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/codeagent.Predict/Predict", grpc.ForceCodec(jsonCodec{}))
+	if err != nil {
+		return nil, fmt.Errorf("open predict stream: %w", err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, fmt.Errorf("send predict request: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("close predict send side: %w", err)
+	}
+
+	out := make(chan *PredictReply)
+	go func() {
+		defer close(out)
+		for {
+			reply := &PredictReply{}
+			if err := stream.RecvMsg(reply); err != nil {
+				return
+			}
+			select {
+			case out <- reply:
+			case <-ctx.Done():
+				return
+			}
+			if reply.Done {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (c *grpcClient) Close() error { return nil }
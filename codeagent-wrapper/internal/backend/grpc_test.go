@@ -0,0 +1,30 @@
+package backend
+
+import "testing"
+
+// TestJSONCodecRoundTrip guards the fix for grpcClient.Predict's SendMsg/
+// RecvMsg calls: grpc-go's default codec type-asserts its argument to
+// proto.Message, which PredictRequest/PredictReply never satisfy, so every
+// Predict call failed at runtime until jsonCodec was wired in via
+// grpc.ForceCodec.
+func TestJSONCodecRoundTrip(t *testing.T) {
+	var codec jsonCodec
+
+	req := &PredictRequest{Model: "m", Prompt: "p", ReasoningEffort: "high", Yolo: true}
+	data, err := codec.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got PredictRequest
+	if err := codec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got != *req {
+		t.Fatalf("round-tripped request = %+v, want %+v", got, *req)
+	}
+
+	if name := codec.Name(); name != "json" {
+		t.Fatalf("Name() = %q, want %q", name, "json")
+	}
+}
@@ -11,6 +11,19 @@ type OpencodeBackend struct{}
 func (OpencodeBackend) Name() string                                 { return "opencode" }
 func (OpencodeBackend) Command() string                              { return "opencode" }
 func (OpencodeBackend) Env(baseURL, apiKey string) map[string]string { return nil }
+
+// ClassifyFailure recognizes Opencode-specific failure wording not covered
+// by the shared patterns in ClassifyFailure.
+func (OpencodeBackend) ClassifyFailure(message string) FailureClass {
+	switch {
+	case MatchesAnyGlob(message, []string{"*rate_limited*", "*step-finish*reason*error*"}):
+		return FailureRateLimited
+	case MatchesAnyGlob(message, []string{"*auth_error*", "*not authenticated*"}):
+		return FailureAuth
+	default:
+		return FailureUnknown
+	}
+}
 func (OpencodeBackend) BuildArgs(cfg *config.Config, targetArg string) []string {
 	args := []string{"run"}
 	if cfg != nil {
@@ -0,0 +1,196 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	config "codeagent-wrapper/internal/config"
+	parser "codeagent-wrapper/internal/parser"
+
+	"github.com/goccy/go-json"
+)
+
+// PluginManifest describes an out-of-process backend discovered under
+// $XDG_CONFIG_HOME/codeagent-wrapper/backends/*.json. ArgsTemplate and the
+// values of EnvTemplate are rendered as Go text/template strings against a
+// pluginTemplateData, letting a manifest build its CLI invocation without
+// any new Go code.
+type PluginManifest struct {
+	Name         string            `json:"name"`
+	Command      string            `json:"command"`
+	ArgsTemplate []string          `json:"args_template"`
+	EnvTemplate  map[string]string `json:"env_template"`
+	// EventFormat selects which StreamParser the plugin's output is parsed
+	// with: one of "codex", "claude", "gemini", "opencode" to reuse a
+	// built-in parser, or "custom" to drive parser.NewSchemaStreamParser
+	// from SchemaMapping.
+	EventFormat   string                `json:"event_format"`
+	SchemaMapping *parser.SchemaMapping `json:"schema_mapping,omitempty"`
+}
+
+// pluginTemplateData is the data available to ArgsTemplate/EnvTemplate
+// entries: .Config and .TargetArg mirror BuildArgs's parameters; .BaseURL
+// and .APIKey mirror Env's, and are empty while rendering ArgsTemplate
+// since BuildArgs isn't given them.
+type pluginTemplateData struct {
+	Config    *config.Config
+	TargetArg string
+	BaseURL   string
+	APIKey    string
+}
+
+func (m PluginManifest) validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("plugin manifest missing name")
+	}
+	if strings.TrimSpace(m.Command) == "" {
+		return fmt.Errorf("plugin manifest %q missing command", m.Name)
+	}
+	switch m.EventFormat {
+	case "codex", "claude", "gemini", "opencode":
+	case "custom":
+		if m.SchemaMapping == nil {
+			return fmt.Errorf("plugin manifest %q declares event_format \"custom\" but has no schema_mapping", m.Name)
+		}
+	case "":
+		return fmt.Errorf("plugin manifest %q missing event_format", m.Name)
+	default:
+		return fmt.Errorf("plugin manifest %q has unknown event_format %q", m.Name, m.EventFormat)
+	}
+	return nil
+}
+
+// pluginBackend implements Backend by rendering a PluginManifest's
+// ArgsTemplate/EnvTemplate.
+type pluginBackend struct {
+	manifest PluginManifest
+}
+
+func (p pluginBackend) Name() string    { return p.manifest.Name }
+func (p pluginBackend) Command() string { return p.manifest.Command }
+
+func (p pluginBackend) BuildArgs(cfg *config.Config, targetArg string) []string {
+	data := pluginTemplateData{Config: cfg, TargetArg: targetArg}
+	args := make([]string, 0, len(p.manifest.ArgsTemplate))
+	for i, tmpl := range p.manifest.ArgsTemplate {
+		rendered, err := renderPluginTemplate(fmt.Sprintf("%s-args-%d", p.manifest.Name, i), tmpl, data)
+		if err != nil {
+			logErrorFn(fmt.Sprintf("plugin backend %q: args_template[%d]: %s", p.manifest.Name, i, err.Error()))
+			continue
+		}
+		if rendered != "" {
+			args = append(args, rendered)
+		}
+	}
+	return args
+}
+
+func (p pluginBackend) Env(baseURL, apiKey string) map[string]string {
+	if len(p.manifest.EnvTemplate) == 0 {
+		return nil
+	}
+	data := pluginTemplateData{BaseURL: strings.TrimSpace(baseURL), APIKey: strings.TrimSpace(apiKey)}
+	env := make(map[string]string, len(p.manifest.EnvTemplate))
+	for key, tmpl := range p.manifest.EnvTemplate {
+		rendered, err := renderPluginTemplate(fmt.Sprintf("%s-env-%s", p.manifest.Name, key), tmpl, data)
+		if err != nil {
+			logErrorFn(fmt.Sprintf("plugin backend %q: env_template[%s]: %s", p.manifest.Name, key, err.Error()))
+			continue
+		}
+		if rendered != "" {
+			env[key] = rendered
+		}
+	}
+	return env
+}
+
+func renderPluginTemplate(name, tmpl string, data pluginTemplateData) (string, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// DefaultPluginDir returns $XDG_CONFIG_HOME/codeagent-wrapper/backends,
+// falling back to ~/.config/codeagent-wrapper/backends per the XDG base
+// directory spec when XDG_CONFIG_HOME is unset.
+func DefaultPluginDir() (string, error) {
+	if xdg := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); xdg != "" {
+		return filepath.Join(xdg, "codeagent-wrapper", "backends"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "codeagent-wrapper", "backends"), nil
+}
+
+// LoadPluginsFromDefaultDir scans DefaultPluginDir for manifests and
+// registers each valid one. It is safe to call when the directory doesn't
+// exist (returns 0, nil). A malformed manifest is logged via logErrorFn and
+// skipped rather than aborting the whole scan.
+func LoadPluginsFromDefaultDir() (int, error) {
+	dir, err := DefaultPluginDir()
+	if err != nil {
+		return 0, err
+	}
+	return LoadPlugins(dir)
+}
+
+// LoadPlugins scans dir for *.json manifests and registers each valid one
+// via registerBackend, returning the number successfully registered. It
+// returns (0, nil) if dir doesn't exist.
+func LoadPlugins(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read plugin backend dir %s: %w", dir, err)
+	}
+
+	registered := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := loadPluginManifest(path); err != nil {
+			logErrorFn(fmt.Sprintf("plugin backend manifest %s: %s", path, err.Error()))
+			continue
+		}
+		registered++
+	}
+	return registered, nil
+}
+
+func loadPluginManifest(path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-controlled plugin directory, not untrusted input
+	if err != nil {
+		return err
+	}
+
+	var manifest PluginManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := manifest.validate(); err != nil {
+		return err
+	}
+
+	if manifest.EventFormat == "custom" {
+		parser.RegisterStreamParser(manifest.Name, parser.NewSchemaStreamParser(manifest.Name, *manifest.SchemaMapping))
+	}
+
+	registerBackend(pluginBackend{manifest: manifest})
+	return nil
+}
@@ -2,19 +2,40 @@ package backend
 
 import (
 	"fmt"
+	"os/exec"
 	"strings"
+	"sync"
 )
 
-var registry = map[string]Backend{
-	"codex":    CodexBackend{},
-	"claude":   ClaudeBackend{},
-	"gemini":   GeminiBackend{},
-	"opencode": OpencodeBackend{},
-}
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Backend{
+		"codex":    CodexBackend{},
+		"claude":   ClaudeBackend{},
+		"gemini":   GeminiBackend{},
+		"opencode": OpencodeBackend{},
+	}
+)
 
-// Registry exposes the available backends. Intended for internal inspection/tests.
+// Registry exposes the available backends, including any registered by
+// LoadPlugins, keyed by their lowercase name. Intended for internal
+// inspection/tests.
 func Registry() map[string]Backend {
-	return registry
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	out := make(map[string]Backend, len(registry))
+	for k, v := range registry {
+		out[k] = v
+	}
+	return out
+}
+
+// registerBackend adds or replaces b under its lowercased name. Used by
+// LoadPlugins to register plugin-provided backends alongside the built-ins.
+func registerBackend(b Backend) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(strings.TrimSpace(b.Name()))] = b
 }
 
 func Select(name string) (Backend, error) {
@@ -22,8 +43,53 @@ func Select(name string) (Backend, error) {
 	if key == "" {
 		key = "codex"
 	}
-	if backend, ok := registry[key]; ok {
+	registryMu.RLock()
+	backend, ok := registry[key]
+	registryMu.RUnlock()
+	if ok {
 		return backend, nil
 	}
 	return nil, fmt.Errorf("unsupported backend %q", name)
 }
+
+// DefaultFallbackOrder is the preference order used for `--backend auto`
+// when no explicit --backend-fallback list is given.
+var DefaultFallbackOrder = []string{"codex", "claude", "gemini", "opencode"}
+
+// Available reports whether name resolves to a known backend whose command
+// is present on $PATH.
+func Available(name string) bool {
+	b, err := Select(name)
+	if err != nil {
+		return false
+	}
+	_, err = exec.LookPath(b.Command())
+	return err == nil
+}
+
+// SelectAvailable returns the first backend in order that resolves and has
+// its command on $PATH. If none are available it returns Select on the
+// first entry, so the caller gets a normal resolution/exec error instead of
+// a bespoke "nothing available" error masking the real cause.
+func SelectAvailable(order []string) (Backend, error) {
+	var firstErr error
+	for _, name := range order {
+		b, err := Select(name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if _, err := exec.LookPath(b.Command()); err == nil {
+			return b, nil
+		}
+	}
+	if len(order) > 0 {
+		return Select(order[0])
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, fmt.Errorf("no backend available")
+}
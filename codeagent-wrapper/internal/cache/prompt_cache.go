@@ -0,0 +1,160 @@
+// Package cache provides a memory-bounded LRU byte cache, used to avoid
+// re-reading large agent prompt files from disk on every invocation.
+package cache
+
+import (
+	"container/list"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+const (
+	defaultBudgetBytes  int64 = 256 << 20 // 256 MiB
+	memoryLimitEnvVar          = "CODEAGENT_MEMORYLIMIT"
+	ramBudgetDivisor    int64 = 16
+)
+
+// entry is a single cached (path, mtime, content) tuple.
+type entry struct {
+	path    string
+	mtimeNs int64
+	data    []byte
+}
+
+// Stats exposes hit/miss/eviction counters for tests and diagnostics.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// LRU is a byte-budgeted, least-recently-used cache. Each insert charges
+// len(data) against the total budget; once the budget is exceeded, the LRU
+// tail is evicted until usage drops back under budget.
+type LRU struct {
+	mu     sync.Mutex
+	budget int64
+	used   int64
+	ll     *list.List // most-recently-used at the front
+	items  map[string]*list.Element
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// New returns an LRU with the given byte budget. A non-positive budget
+// disables caching: every Get misses and nothing is retained.
+func New(budgetBytes int64) *LRU {
+	return &LRU{
+		budget: budgetBytes,
+		ll:     list.New(),
+		items:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached bytes for path if present and mtimeNs matches the
+// stored entry; otherwise it reports a miss (and evicts any stale entry).
+func (c *LRU) Get(path string, mtimeNs int64) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[path]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if e.mtimeNs != mtimeNs {
+		// Stale: the file changed since we cached it.
+		c.removeElementLocked(el)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	c.hits.Add(1)
+	return e.data, true
+}
+
+// Put inserts or replaces the cached bytes for path, evicting LRU-tail
+// entries as needed to stay within budget.
+func (c *LRU) Put(path string, mtimeNs int64, data []byte) {
+	if c.budget <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[path]; ok {
+		c.removeElementLocked(el)
+	}
+
+	e := &entry{path: path, mtimeNs: mtimeNs, data: data}
+	el := c.ll.PushFront(e)
+	c.items[path] = el
+	c.used += int64(len(data))
+
+	for c.used > c.budget {
+		back := c.ll.Back()
+		if back == nil {
+			break
+		}
+		c.removeElementLocked(back)
+		c.evictions.Add(1)
+	}
+}
+
+func (c *LRU) removeElementLocked(el *list.Element) {
+	e := el.Value.(*entry)
+	delete(c.items, e.path)
+	c.ll.Remove(el)
+	c.used -= int64(len(e.data))
+}
+
+// Stats returns a snapshot of the hit/miss/eviction counters.
+func (c *LRU) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// Reset clears all cached entries and counters. Intended for tests.
+func (c *LRU) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.used = 0
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.evictions.Store(0)
+}
+
+// DefaultBudget computes min(256 MiB, totalRAM/16), overridable via
+// CODEAGENT_MEMORYLIMIT (a float number of GiB, mirroring Hugo's
+// HUGO_MEMORYLIMIT).
+func DefaultBudget() int64 {
+	if raw, ok := os.LookupEnv(memoryLimitEnvVar); ok {
+		raw = strings.TrimSpace(raw)
+		if gib, err := strconv.ParseFloat(raw, 64); err == nil && gib > 0 {
+			return int64(gib * (1 << 30))
+		}
+	}
+
+	budget := defaultBudgetBytes
+	if vm, err := mem.VirtualMemory(); err == nil && vm.Total > 0 {
+		if ramShare := int64(vm.Total) / ramBudgetDivisor; ramShare < budget {
+			budget = ramShare
+		}
+	}
+	return budget
+}
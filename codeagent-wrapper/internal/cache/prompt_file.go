@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	promptCacheOnce sync.Once
+	promptCache     *LRU
+)
+
+func sharedPromptCache() *LRU {
+	promptCacheOnce.Do(func() {
+		promptCache = New(DefaultBudget())
+	})
+	return promptCache
+}
+
+// LoadPromptFile reads path (expanding a leading "~"), serving the content
+// from the shared LRU cache when the file's mtime hasn't changed since it was
+// last read.
+func LoadPromptFile(path string) ([]byte, error) {
+	expanded, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(expanded)
+	if err != nil {
+		return nil, err
+	}
+	mtimeNs := info.ModTime().UnixNano()
+
+	c := sharedPromptCache()
+	if data, ok := c.Get(expanded, mtimeNs); ok {
+		return data, nil
+	}
+
+	data, err := os.ReadFile(expanded) // #nosec G304 -- caller is responsible for path validation
+	if err != nil {
+		return nil, err
+	}
+	c.Put(expanded, mtimeNs, data)
+	return data, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~\\") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if path == "~" {
+		return home, nil
+	}
+	return filepath.Join(home, path[2:]), nil
+}
+
+// PromptCacheStats returns the shared prompt cache's hit/miss/eviction counters.
+func PromptCacheStats() Stats {
+	return sharedPromptCache().Stats()
+}
+
+// ResetPromptCacheForTest clears the shared prompt cache and its counters.
+func ResetPromptCacheForTest() {
+	sharedPromptCache().Reset()
+}
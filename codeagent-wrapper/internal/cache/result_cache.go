@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// CachePolicy controls how RunWithCache (internal/executor) treats a task's
+// result cache lookup/write.
+type CachePolicy string
+
+const (
+	CachePolicyOff       CachePolicy = ""
+	CachePolicyReadOnly  CachePolicy = "read-only"
+	CachePolicyReadWrite CachePolicy = "read-write"
+	CachePolicyRefresh   CachePolicy = "refresh"
+)
+
+// CachedResult is the on-disk shape of one cached task outcome: the raw
+// TaskResult JSON (opaque to this package, so internal/cache doesn't need to
+// import internal/executor) plus enough metadata to prune by age/size.
+type CachedResult struct {
+	Result   json.RawMessage `json:"result"`
+	StoredAt time.Time       `json:"stored_at"`
+}
+
+// ResultCache is a content-addressed store of CachedResult values, sharded on
+// disk by the first 2 hex characters of the key to avoid a single directory
+// with huge fan-out.
+type ResultCache struct {
+	dir string
+}
+
+// NewResultCache returns a ResultCache rooted at dir, creating it if needed.
+func NewResultCache(dir string) (*ResultCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ResultCache{dir: dir}, nil
+}
+
+func (c *ResultCache) pathFor(key string) (string, error) {
+	if len(key) < 2 {
+		return "", errors.New("cache key too short")
+	}
+	return filepath.Join(c.dir, key[:2], key+".json"), nil
+}
+
+// Get looks up key, returning (entry, true) on a hit.
+func (c *ResultCache) Get(key string) (CachedResult, bool) {
+	path, err := c.pathFor(key)
+	if err != nil {
+		return CachedResult{}, false
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is derived from a sha256 hex digest under c.dir, not user input
+	if err != nil {
+		return CachedResult{}, false
+	}
+	var entry CachedResult
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CachedResult{}, false
+	}
+	return entry, true
+}
+
+// Put stores entry under key, overwriting any existing entry.
+func (c *ResultCache) Put(key string, entry CachedResult) error {
+	path, err := c.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = time.Now()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644) // #nosec G306 -- cached task output, not secret material
+}
+
+// Prune removes cache entries older than olderThan and, if the remaining
+// total still exceeds maxSizeBytes, removes further entries oldest-first
+// until it fits. maxSizeBytes <= 0 disables the size bound. It returns the
+// number of entries removed and bytes freed.
+func (c *ResultCache) Prune(olderThan time.Duration, maxSizeBytes int64) (removed int, freedBytes int64, err error) {
+	type fileInfo struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []fileInfo
+
+	walkErr := filepath.WalkDir(c.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, fileInfo{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if walkErr != nil {
+		return 0, 0, walkErr
+	}
+
+	now := time.Now()
+	var kept []fileInfo
+	var total int64
+	for _, f := range files {
+		if olderThan > 0 && now.Sub(f.modTime) > olderThan {
+			if rmErr := os.Remove(f.path); rmErr == nil {
+				removed++
+				freedBytes += f.size
+			}
+			continue
+		}
+		kept = append(kept, f)
+		total += f.size
+	}
+
+	if maxSizeBytes > 0 && total > maxSizeBytes {
+		sort.Slice(kept, func(i, j int) bool { return kept[i].modTime.Before(kept[j].modTime) })
+		for _, f := range kept {
+			if total <= maxSizeBytes {
+				break
+			}
+			if rmErr := os.Remove(f.path); rmErr == nil {
+				removed++
+				freedBytes += f.size
+				total -= f.size
+			}
+		}
+	}
+
+	return removed, freedBytes, nil
+}
+
+// FingerprintKey computes the content-address for a task attempt:
+// sha256(backend || model || reasoningEffort || normalized(prompt) || workdirTreeHash),
+// hex-encoded.
+func FingerprintKey(backend, model, reasoningEffort, prompt, workdirTreeHash string) string {
+	h := sha256.New()
+	for _, part := range []string{backend, model, reasoningEffort, normalizePrompt(prompt), workdirTreeHash} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func normalizePrompt(prompt string) string {
+	lines := strings.Split(prompt, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// WorkdirTreeHash fingerprints the contents of workdir: for a git repo, the
+// hash is derived from `git ls-files -s` (tracked file modes, blob hashes
+// and paths, without the noise of build artifacts or mtimes) plus
+// `git status --porcelain` (which paths are dirty, staged or untracked) plus
+// `git diff` (the content of unstaged edits to tracked files) - since
+// `ls-files -s` reports the index's blob hashes, editing a tracked file
+// without `git add`ing it would otherwise leave the cache key unchanged and
+// replay a stale result. For a non-git directory it falls back to walking the tree
+// and hashing path+size+mtime for every regular file.
+func WorkdirTreeHash(workdir string) (string, error) {
+	lsFiles, err := exec.Command("git", "-C", workdir, "ls-files", "-s").Output() // #nosec G204 -- workdir is the task's own configured working directory, not attacker-controlled input
+	if err == nil {
+		status, statusErr := exec.Command("git", "-C", workdir, "status", "--porcelain").Output() // #nosec G204 -- workdir is the task's own configured working directory, not attacker-controlled input
+		if statusErr != nil {
+			return "", statusErr
+		}
+		diff, diffErr := exec.Command("git", "-C", workdir, "diff").Output() // #nosec G204 -- workdir is the task's own configured working directory, not attacker-controlled input
+		if diffErr != nil {
+			return "", diffErr
+		}
+		h := sha256.New()
+		h.Write(lsFiles)
+		h.Write([]byte{0})
+		h.Write(status)
+		h.Write([]byte{0})
+		h.Write(diff)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	h := sha256.New()
+	err = filepath.WalkDir(workdir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(workdir, path)
+		if err != nil {
+			rel = path
+		}
+		h.Write([]byte(rel))
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.FormatInt(info.Size(), 10)))
+		h.Write([]byte{0})
+		h.Write([]byte(strconv.FormatInt(info.ModTime().UnixNano(), 10)))
+		h.Write([]byte{0})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DefaultResultCacheDir returns ~/.codeagent/cache/results, creating no
+// directories itself (see NewResultCache).
+func DefaultResultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".codeagent", "cache", "results"), nil
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	ilogger "codeagent-wrapper/internal/logger"
 
@@ -13,8 +14,31 @@ import (
 )
 
 type BackendConfig struct {
-	BaseURL string `json:"base_url,omitempty"`
-	APIKey  string `json:"api_key,omitempty"`
+	Protocol string `json:"protocol,omitempty"` // "http" (default) or "grpc"
+	BaseURL  string `json:"base_url,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+
+	// gRPC-specific fields, only consulted when Protocol == "grpc".
+	GRPCTarget      string            `json:"grpc_target,omitempty"`
+	GRPCTLSCertPath string            `json:"grpc_tls_cert_path,omitempty"`
+	GRPCInsecure    bool              `json:"grpc_insecure,omitempty"`
+	GRPCAuthMeta    map[string]string `json:"grpc_auth_metadata,omitempty"`
+}
+
+// ProtocolHTTP and ProtocolGRPC are the recognized BackendConfig.Protocol values.
+// An empty Protocol is treated as ProtocolHTTP for backward compatibility.
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// NormalizedProtocol returns the backend's protocol, defaulting to ProtocolHTTP.
+func (b BackendConfig) NormalizedProtocol() string {
+	p := strings.ToLower(strings.TrimSpace(b.Protocol))
+	if p == "" {
+		return ProtocolHTTP
+	}
+	return p
 }
 
 type AgentModelConfig struct {
@@ -26,6 +50,29 @@ type AgentModelConfig struct {
 	Reasoning   string `json:"reasoning,omitempty"`
 	BaseURL     string `json:"base_url,omitempty"`
 	APIKey      string `json:"api_key,omitempty"`
+
+	// MaxMemoryMB, MaxCPUPercent, and MaxWallSeconds bound this agent's
+	// spawned child process once it's running, sampled live on a ticker
+	// (internal/executor's ResourceSampler) rather than enforced by the
+	// kernel at launch the way resources.Limits/cgroups are. Zero means no
+	// cap. See ResourceBudget.
+	MaxMemoryMB    int64   `json:"max_memory_mb,omitempty"`
+	MaxCPUPercent  float64 `json:"max_cpu_percent,omitempty"`
+	MaxWallSeconds int     `json:"max_wall_seconds,omitempty"`
+}
+
+// ResourceBudget is the resolved, agent-agnostic form of AgentModelConfig's
+// MaxMemoryMB/MaxCPUPercent/MaxWallSeconds fields, as returned by
+// ResolveAgentConfig.
+type ResourceBudget struct {
+	MaxMemoryMB    int64
+	MaxCPUPercent  float64
+	MaxWallSeconds int
+}
+
+// IsZero reports whether no live-sampled limit is set.
+func (b ResourceBudget) IsZero() bool {
+	return b == ResourceBudget{}
 }
 
 type ModelsConfig struct {
@@ -35,46 +82,50 @@ type ModelsConfig struct {
 	Backends       map[string]BackendConfig    `json:"backends,omitempty"`
 }
 
-var defaultModelsConfig = ModelsConfig{
-	DefaultBackend: "opencode",
-	DefaultModel:   "opencode/grok-code",
-	Agents: map[string]AgentModelConfig{
-		"oracle":                  {Backend: "claude", Model: "claude-opus-4-5-20251101", PromptFile: "~/.claude/skills/omo/references/oracle.md", Description: "Technical advisor"},
-		"librarian":               {Backend: "claude", Model: "claude-sonnet-4-5-20250929", PromptFile: "~/.claude/skills/omo/references/librarian.md", Description: "Researcher"},
-		"explore":                 {Backend: "opencode", Model: "opencode/grok-code", PromptFile: "~/.claude/skills/omo/references/explore.md", Description: "Code search"},
-		"develop":                 {Backend: "codex", Model: "", PromptFile: "~/.claude/skills/omo/references/develop.md", Description: "Code development"},
-		"frontend-ui-ux-engineer": {Backend: "gemini", Model: "", PromptFile: "~/.claude/skills/omo/references/frontend-ui-ux-engineer.md", Description: "Frontend engineer"},
-		"document-writer":         {Backend: "gemini", Model: "", PromptFile: "~/.claude/skills/omo/references/document-writer.md", Description: "Documentation"},
-	},
+// defaultModelsConfig returns the effective built-in defaults: a fixed
+// fallback backend/model plus whatever agents sub-packages have registered
+// via RegisterAgent (see config/builtins for the six shipped agents). It is
+// recomputed on every call so tests that register ephemeral agents see them
+// without restarting the process.
+func defaultModelsConfig() ModelsConfig {
+	return ModelsConfig{
+		DefaultBackend: "opencode",
+		DefaultModel:   "opencode/grok-code",
+		Agents:         registeredAgentDefaults(),
+	}
 }
 
 var (
-	modelsConfigOnce   sync.Once
-	modelsConfigCached *ModelsConfig
+	modelsConfigOnce sync.Once
+	modelsConfigPtr  atomic.Pointer[ModelsConfig]
 )
 
 func modelsConfig() *ModelsConfig {
 	modelsConfigOnce.Do(func() {
-		modelsConfigCached = loadModelsConfig()
+		modelsConfigPtr.Store(loadModelsConfig())
+		startModelsConfigWatcher()
 	})
-	if modelsConfigCached == nil {
-		return &defaultModelsConfig
+	if cached := modelsConfigPtr.Load(); cached != nil {
+		return cached
 	}
-	return modelsConfigCached
+	defaults := defaultModelsConfig()
+	return &defaults
 }
 
 func loadModelsConfig() *ModelsConfig {
+	defaults := defaultModelsConfig()
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		ilogger.LogWarn(fmt.Sprintf("Failed to resolve home directory for models config: %v; using defaults", err))
-		return &defaultModelsConfig
+		return &defaults
 	}
 
 	configDir := filepath.Clean(filepath.Join(home, ".codeagent"))
 	configPath := filepath.Clean(filepath.Join(configDir, "models.json"))
 	rel, err := filepath.Rel(configDir, configPath)
 	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
-		return &defaultModelsConfig
+		return &defaults
 	}
 
 	data, err := os.ReadFile(configPath) // #nosec G304 -- path is fixed under user home and validated to stay within configDir
@@ -82,26 +133,26 @@ func loadModelsConfig() *ModelsConfig {
 		if !os.IsNotExist(err) {
 			ilogger.LogWarn(fmt.Sprintf("Failed to read models config %s: %v; using defaults", configPath, err))
 		}
-		return &defaultModelsConfig
+		return &defaults
 	}
 
 	var cfg ModelsConfig
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		ilogger.LogWarn(fmt.Sprintf("Failed to parse models config %s: %v; using defaults", configPath, err))
-		return &defaultModelsConfig
+		return &defaults
 	}
 
 	cfg.DefaultBackend = strings.TrimSpace(cfg.DefaultBackend)
 	if cfg.DefaultBackend == "" {
-		cfg.DefaultBackend = defaultModelsConfig.DefaultBackend
+		cfg.DefaultBackend = defaults.DefaultBackend
 	}
 	cfg.DefaultModel = strings.TrimSpace(cfg.DefaultModel)
 	if cfg.DefaultModel == "" {
-		cfg.DefaultModel = defaultModelsConfig.DefaultModel
+		cfg.DefaultModel = defaults.DefaultModel
 	}
 
 	// Merge with defaults
-	for name, agent := range defaultModelsConfig.Agents {
+	for name, agent := range defaults.Agents {
 		if _, exists := cfg.Agents[name]; !exists {
 			if cfg.Agents == nil {
 				cfg.Agents = make(map[string]AgentModelConfig)
@@ -152,7 +203,20 @@ func LoadDynamicAgent(name string) (AgentModelConfig, bool) {
 func ResolveBackendConfig(backendName string) (baseURL, apiKey string) {
 	cfg := modelsConfig()
 	resolved := resolveBackendConfig(cfg, backendName)
-	return strings.TrimSpace(resolved.BaseURL), strings.TrimSpace(resolved.APIKey)
+	return resolveSecretRef(strings.TrimSpace(resolved.BaseURL)), resolveSecretRef(strings.TrimSpace(resolved.APIKey))
+}
+
+// ResolveBackendConfigFull returns the complete resolved BackendConfig (including
+// protocol and gRPC fields), so callers that need more than base_url/api_key -
+// such as a gRPC client dialer - don't have to re-implement backend-key lookup.
+// BaseURL and APIKey are resolved through resolveSecretRef, same as
+// ResolveBackendConfig.
+func ResolveBackendConfigFull(backendName string) BackendConfig {
+	cfg := modelsConfig()
+	resolved := resolveBackendConfig(cfg, backendName)
+	resolved.BaseURL = resolveSecretRef(strings.TrimSpace(resolved.BaseURL))
+	resolved.APIKey = resolveSecretRef(strings.TrimSpace(resolved.APIKey))
+	return resolved
 }
 
 func resolveBackendConfig(cfg *ModelsConfig, backendName string) BackendConfig {
@@ -173,6 +237,11 @@ func resolveBackendConfig(cfg *ModelsConfig, backendName string) BackendConfig {
 }
 
 func resolveAgentConfig(agentName string) (backend, model, promptFile, reasoning, baseURL, apiKey string, yolo bool) {
+	backend, model, promptFile, reasoning, baseURL, apiKey, yolo, _ = resolveAgentConfigWithBudget(agentName)
+	return backend, model, promptFile, reasoning, baseURL, apiKey, yolo
+}
+
+func resolveAgentConfigWithBudget(agentName string) (backend, model, promptFile, reasoning, baseURL, apiKey string, yolo bool, budget ResourceBudget) {
 	cfg := modelsConfig()
 	if agent, ok := cfg.Agents[agentName]; ok {
 		backend = strings.TrimSpace(agent.Backend)
@@ -190,7 +259,12 @@ func resolveAgentConfig(agentName string) (backend, model, promptFile, reasoning
 			apiKey = strings.TrimSpace(backendCfg.APIKey)
 		}
 
-		return backend, strings.TrimSpace(agent.Model), agent.PromptFile, agent.Reasoning, baseURL, apiKey, agent.Yolo
+		budget = ResourceBudget{
+			MaxMemoryMB:    agent.MaxMemoryMB,
+			MaxCPUPercent:  agent.MaxCPUPercent,
+			MaxWallSeconds: agent.MaxWallSeconds,
+		}
+		return backend, strings.TrimSpace(agent.Model), agent.PromptFile, agent.Reasoning, resolveSecretRef(baseURL), resolveSecretRef(apiKey), agent.Yolo, budget
 	}
 
 	if dynamic, ok := LoadDynamicAgent(agentName); ok {
@@ -199,7 +273,7 @@ func resolveAgentConfig(agentName string) (backend, model, promptFile, reasoning
 		backendCfg := resolveBackendConfig(cfg, backend)
 		baseURL = strings.TrimSpace(backendCfg.BaseURL)
 		apiKey = strings.TrimSpace(backendCfg.APIKey)
-		return backend, model, dynamic.PromptFile, "", baseURL, apiKey, false
+		return backend, model, dynamic.PromptFile, "", resolveSecretRef(baseURL), resolveSecretRef(apiKey), false, ResourceBudget{}
 	}
 
 	backend = cfg.DefaultBackend
@@ -207,14 +281,15 @@ func resolveAgentConfig(agentName string) (backend, model, promptFile, reasoning
 	backendCfg := resolveBackendConfig(cfg, backend)
 	baseURL = strings.TrimSpace(backendCfg.BaseURL)
 	apiKey = strings.TrimSpace(backendCfg.APIKey)
-	return backend, model, "", "", baseURL, apiKey, false
+	return backend, model, "", "", resolveSecretRef(baseURL), resolveSecretRef(apiKey), false, ResourceBudget{}
 }
 
-func ResolveAgentConfig(agentName string) (backend, model, promptFile, reasoning, baseURL, apiKey string, yolo bool) {
-	return resolveAgentConfig(agentName)
+func ResolveAgentConfig(agentName string) (backend, model, promptFile, reasoning, baseURL, apiKey string, yolo bool, budget ResourceBudget) {
+	return resolveAgentConfigWithBudget(agentName)
 }
 
 func ResetModelsConfigCacheForTest() {
-	modelsConfigCached = nil
+	stopModelsConfigWatcher()
+	modelsConfigPtr.Store(nil)
 	modelsConfigOnce = sync.Once{}
 }
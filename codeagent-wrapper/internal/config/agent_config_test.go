@@ -6,6 +6,54 @@ import (
 	"testing"
 )
 
+// config/builtins registers these same presets via RegisterAgent, but it
+// also imports this package - blank-importing it here to satisfy the tests
+// below would be an import cycle. Register the presets these tests assert on
+// directly instead.
+func init() {
+	RegisterAgent("oracle", AgentModelConfig{
+		Backend:     "claude",
+		Model:       "claude-opus-4-5-20251101",
+		PromptFile:  "~/.claude/skills/omo/references/oracle.md",
+		Description: "Technical advisor",
+	}, 100)
+
+	RegisterAgent("librarian", AgentModelConfig{
+		Backend:     "claude",
+		Model:       "claude-sonnet-4-5-20250929",
+		PromptFile:  "~/.claude/skills/omo/references/librarian.md",
+		Description: "Researcher",
+	}, 100)
+
+	RegisterAgent("explore", AgentModelConfig{
+		Backend:     "opencode",
+		Model:       "opencode/grok-code",
+		PromptFile:  "~/.claude/skills/omo/references/explore.md",
+		Description: "Code search",
+	}, 100)
+
+	RegisterAgent("develop", AgentModelConfig{
+		Backend:     "codex",
+		Model:       "",
+		PromptFile:  "~/.claude/skills/omo/references/develop.md",
+		Description: "Code development",
+	}, 100)
+
+	RegisterAgent("frontend-ui-ux-engineer", AgentModelConfig{
+		Backend:     "gemini",
+		Model:       "",
+		PromptFile:  "~/.claude/skills/omo/references/frontend-ui-ux-engineer.md",
+		Description: "Frontend engineer",
+	}, 100)
+
+	RegisterAgent("document-writer", AgentModelConfig{
+		Backend:     "gemini",
+		Model:       "",
+		PromptFile:  "~/.claude/skills/omo/references/document-writer.md",
+		Description: "Documentation",
+	}, 100)
+}
+
 func TestResolveAgentConfig_Defaults(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -153,7 +201,7 @@ func TestLoadModelsConfig_WithFile(t *testing.T) {
 		t.Errorf("ResolveBackendConfig(apiKey) = %q, want %q", apiKey, "backend-key")
 	}
 
-	backend, model, _, _, agentBaseURL, agentAPIKey, _ := ResolveAgentConfig("custom-agent")
+	backend, model, _, _, agentBaseURL, agentAPIKey, _, _ := ResolveAgentConfig("custom-agent")
 	if backend != "codex" {
 		t.Errorf("ResolveAgentConfig(backend) = %q, want %q", backend, "codex")
 	}
@@ -0,0 +1,57 @@
+// Package builtins registers the six agent presets this wrapper has always
+// shipped with (oracle, librarian, explore, develop, frontend-ui-ux-engineer,
+// document-writer). Importing this package for its side effect - typically a
+// blank import from the command entrypoint - is what makes them resolvable;
+// the config package itself no longer hard-codes them.
+package builtins
+
+import config "codeagent-wrapper/internal/config"
+
+// builtinPriority is the priority used for every agent registered here.
+// Lower values win ties, so downstream consumers that want to override a
+// built-in preset should register with a priority below this.
+const builtinPriority = 100
+
+func init() {
+	config.RegisterAgent("oracle", config.AgentModelConfig{
+		Backend:     "claude",
+		Model:       "claude-opus-4-5-20251101",
+		PromptFile:  "~/.claude/skills/omo/references/oracle.md",
+		Description: "Technical advisor",
+	}, builtinPriority)
+
+	config.RegisterAgent("librarian", config.AgentModelConfig{
+		Backend:     "claude",
+		Model:       "claude-sonnet-4-5-20250929",
+		PromptFile:  "~/.claude/skills/omo/references/librarian.md",
+		Description: "Researcher",
+	}, builtinPriority)
+
+	config.RegisterAgent("explore", config.AgentModelConfig{
+		Backend:     "opencode",
+		Model:       "opencode/grok-code",
+		PromptFile:  "~/.claude/skills/omo/references/explore.md",
+		Description: "Code search",
+	}, builtinPriority)
+
+	config.RegisterAgent("develop", config.AgentModelConfig{
+		Backend:     "codex",
+		Model:       "",
+		PromptFile:  "~/.claude/skills/omo/references/develop.md",
+		Description: "Code development",
+	}, builtinPriority)
+
+	config.RegisterAgent("frontend-ui-ux-engineer", config.AgentModelConfig{
+		Backend:     "gemini",
+		Model:       "",
+		PromptFile:  "~/.claude/skills/omo/references/frontend-ui-ux-engineer.md",
+		Description: "Frontend engineer",
+	}, builtinPriority)
+
+	config.RegisterAgent("document-writer", config.AgentModelConfig{
+		Backend:     "gemini",
+		Model:       "",
+		PromptFile:  "~/.claude/skills/omo/references/document-writer.md",
+		Description: "Documentation",
+	}, builtinPriority)
+}
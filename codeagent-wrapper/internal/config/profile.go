@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Profile is one entry under the `profiles:` table in a config file, e.g.
+//
+//	profiles:
+//	  review:
+//	    backend: claude
+//	    agent: oracle
+//
+// Empty fields mean "not set by this profile" and fall through to the
+// top-level config, agent preset, or built-in default.
+type Profile struct {
+	Backend         string
+	Model           string
+	Agent           string
+	PromptFile      string
+	ReasoningEffort string
+	SkipPermissions bool
+}
+
+// ResolveProfile looks up "profiles.<name>" in v. An empty name is not an
+// error and returns (nil, nil) - no profile was requested. A non-empty name
+// that doesn't exist in the config is an error, since a typo'd --profile
+// should fail loudly rather than silently fall back to defaults.
+func ResolveProfile(v *viper.Viper, name string) (*Profile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	if v == nil {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+
+	key := "profiles." + name
+	sub := v.Sub(key)
+	if sub == nil {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+
+	return &Profile{
+		Backend:         strings.TrimSpace(sub.GetString("backend")),
+		Model:           strings.TrimSpace(sub.GetString("model")),
+		Agent:           strings.TrimSpace(sub.GetString("agent")),
+		PromptFile:      strings.TrimSpace(sub.GetString("prompt-file")),
+		ReasoningEffort: strings.TrimSpace(sub.GetString("reasoning-effort")),
+		SkipPermissions: sub.GetBool("skip-permissions"),
+	}, nil
+}
+
+// EnvOverride returns the value of the CODEAGENT_<KEY> environment variable
+// for a hyphenated flag/config key (e.g. "reasoning-effort" ->
+// CODEAGENT_REASONING_EFFORT), or "" if unset. It lets callers check for an
+// explicit environment override independently of a config file value, since
+// viper's AutomaticEnv otherwise folds both into a single GetString result.
+func EnvOverride(key string) string {
+	envKey := "CODEAGENT_" + strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(key), "-", "_"))
+	return strings.TrimSpace(os.Getenv(envKey))
+}
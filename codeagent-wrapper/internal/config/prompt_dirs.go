@@ -0,0 +1,87 @@
+package config
+
+import (
+	"strings"
+	"sync"
+)
+
+// promptAllowedDirsOnce/-Val cache the parsed "prompt.allowed_dirs" value for
+// the life of the process: the allowlist rarely changes, and re-parsing
+// ~/.codeagent/config.(yaml|json|toml) on every ReadAgentPromptFile call
+// would be wasted work. ResetPromptAllowedDirsForTest clears it for tests.
+var (
+	promptAllowedDirsOnce sync.Once
+	promptAllowedDirsVal  []string
+)
+
+// PromptAllowedDirs returns the user-configured extra directories that
+// executor.ReadAgentPromptFile should treat as allowed, on top of its two
+// built-in defaults (~/.claude and ~/.codeagent/agents). The value comes
+// from the "prompt.allowed_dirs" key in ~/.codeagent/config.(yaml|json|toml)
+// (see NewViper), or the CODEAGENT_PROMPT_ALLOWED_DIRS env var, which is
+// colon- or semicolon-separated rather than a YAML/JSON list.
+func PromptAllowedDirs() []string {
+	promptAllowedDirsOnce.Do(func() {
+		promptAllowedDirsVal = loadPromptAllowedDirs()
+	})
+	return promptAllowedDirsVal
+}
+
+func loadPromptAllowedDirs() []string {
+	v, err := NewViper("")
+	if err != nil {
+		return nil
+	}
+	// NewViper's env key replacer only normalizes "-", so it wouldn't derive
+	// CODEAGENT_PROMPT_ALLOWED_DIRS (underscore) from the dotted
+	// "prompt.allowed_dirs" key on its own; bind it explicitly.
+	_ = v.BindEnv("prompt.allowed_dirs", "CODEAGENT_PROMPT_ALLOWED_DIRS")
+	return parsePromptAllowedDirs(v.Get("prompt.allowed_dirs"))
+}
+
+// parsePromptAllowedDirs accepts either form viper can hand back for this
+// key: a YAML/JSON list ([]interface{}) from the config file, or a single
+// colon/semicolon-separated string from CODEAGENT_PROMPT_ALLOWED_DIRS.
+func parsePromptAllowedDirs(raw interface{}) []string {
+	switch val := raw.(type) {
+	case string:
+		return splitPromptDirList(val)
+	case []string:
+		return val
+	case []interface{}:
+		dirs := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				dirs = append(dirs, s)
+			}
+		}
+		return dirs
+	default:
+		return nil
+	}
+}
+
+// splitPromptDirList splits raw on ';' if present (Windows-style, so a
+// "C:\Users\..." entry isn't mangled), otherwise on ':' (Unix PATH-style).
+func splitPromptDirList(raw string) []string {
+	sep := ":"
+	if strings.Contains(raw, ";") {
+		sep = ";"
+	}
+
+	var dirs []string
+	for _, part := range strings.Split(raw, sep) {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			dirs = append(dirs, part)
+		}
+	}
+	return dirs
+}
+
+// ResetPromptAllowedDirsForTest clears the cached PromptAllowedDirs result so
+// tests can exercise different config file/env values.
+func ResetPromptAllowedDirsForTest() {
+	promptAllowedDirsOnce = sync.Once{}
+	promptAllowedDirsVal = nil
+}
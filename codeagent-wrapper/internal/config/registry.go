@@ -0,0 +1,61 @@
+package config
+
+import (
+	"sort"
+	"sync"
+)
+
+// registeredAgent is one entry contributed via RegisterAgent.
+type registeredAgent struct {
+	name     string
+	spec     AgentModelConfig
+	priority int
+	seq      int // registration order, used to break priority ties deterministically
+}
+
+var (
+	agentRegistryMu  sync.Mutex
+	agentRegistry    []registeredAgent
+	agentRegistrySeq int
+)
+
+// RegisterAgent adds (or, for a repeated name, re-registers) a built-in agent
+// preset. It is meant to be called from init() in sub-packages - see
+// config/builtins for the six agents this wrapper ships with - so new agents
+// can be added without editing this package's core map. Lower priority values
+// win when the same name is registered more than once; the user's
+// ~/.codeagent/models.json always wins over anything registered here.
+func RegisterAgent(name string, spec AgentModelConfig, priority int) {
+	agentRegistryMu.Lock()
+	defer agentRegistryMu.Unlock()
+	agentRegistrySeq++
+	agentRegistry = append(agentRegistry, registeredAgent{name: name, spec: spec, priority: priority, seq: agentRegistrySeq})
+}
+
+// registeredAgentDefaults builds the effective built-in agent map by applying
+// every RegisterAgent call in priority order (highest first, lowest last;
+// ties broken by registration order), so a later registration at the same
+// priority, or any registration at a lower priority, ends up written last
+// and so wins - matching RegisterAgent's documented "lower priority values
+// win" contract.
+func registeredAgentDefaults() map[string]AgentModelConfig {
+	agentRegistryMu.Lock()
+	entries := make([]registeredAgent, len(agentRegistry))
+	copy(entries, agentRegistry)
+	agentRegistryMu.Unlock()
+
+	// Stable sort by priority (descending, so the lowest priority value is
+	// applied last and wins); ties keep registration order.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].priority != entries[j].priority {
+			return entries[i].priority > entries[j].priority
+		}
+		return entries[i].seq < entries[j].seq
+	})
+
+	out := make(map[string]AgentModelConfig, len(entries))
+	for _, e := range entries {
+		out[e.name] = e.spec
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+// TestRegisteredAgentDefaultsLowerPriorityWins guards RegisterAgent's
+// documented contract ("lower priority values win when the same name is
+// registered more than once"): registeredAgentDefaults previously sorted
+// ascending and wrote entries into the map in that order, so the *highest*
+// priority registration ended up written last and won - the inverse of the
+// documented behavior.
+func TestRegisteredAgentDefaultsLowerPriorityWins(t *testing.T) {
+	agentRegistryMu.Lock()
+	saved := make([]registeredAgent, len(agentRegistry))
+	copy(saved, agentRegistry)
+	agentRegistryMu.Unlock()
+	defer func() {
+		agentRegistryMu.Lock()
+		agentRegistry = saved
+		agentRegistryMu.Unlock()
+	}()
+
+	high := AgentModelConfig{Backend: "claude", Model: "high-priority-model"}
+	low := AgentModelConfig{Backend: "claude", Model: "low-priority-model"}
+
+	RegisterAgent("dup-priority-test", high, 100)
+	RegisterAgent("dup-priority-test", low, 50)
+
+	got := registeredAgentDefaults()["dup-priority-test"]
+	if got != low {
+		t.Fatalf("expected lower-priority registration to win, got %+v, want %+v", got, low)
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// jsonSchema is the minimal subset of JSON Schema draft-07 this package
+// emits - enough for editor autocompletion on models.json, not a general
+// purpose schema compiler.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+}
+
+// GenerateModelsConfigSchema emits a JSON Schema draft-07 document derived
+// from ModelsConfig (and the structs it embeds) via reflection, so users can
+// set `"$schema"` in ~/.codeagent/models.json and get editor completion.
+func GenerateModelsConfigSchema() []byte {
+	root := structToSchema(reflect.TypeOf(ModelsConfig{}))
+	root.Schema = "http://json-schema.org/draft-07/schema#"
+	root.Title = "codeagent models config"
+
+	if agents, ok := root.Properties["agents"]; ok {
+		agents.AdditionalProperties = structToSchema(reflect.TypeOf(AgentModelConfig{}))
+		if field, ok := agents.AdditionalProperties.Properties["reasoning"]; ok {
+			field.Enum = []string{"", "low", "medium", "high"}
+		}
+	}
+	if backends, ok := root.Properties["backends"]; ok {
+		backends.AdditionalProperties = structToSchema(reflect.TypeOf(BackendConfig{}))
+		if field, ok := backends.AdditionalProperties.Properties["protocol"]; ok {
+			field.Enum = []string{ProtocolHTTP, ProtocolGRPC}
+		}
+	}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// structToSchema builds a "type: object" schema from a Go struct's exported
+// fields, keyed by their `json` tag name.
+func structToSchema(t reflect.Type) *jsonSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema := &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitempty := jsonFieldName(field)
+		if name == "-" {
+			continue
+		}
+		schema.Properties[name] = fieldToSchema(field.Type)
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+func fieldToSchema(t reflect.Type) *jsonSchema {
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: fieldToSchema(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: "array", Items: fieldToSchema(t.Elem())}
+	case reflect.Struct:
+		return structToSchema(t)
+	case reflect.Ptr:
+		return fieldToSchema(t.Elem())
+	default:
+		return &jsonSchema{}
+	}
+}
+
+// jsonFieldName extracts the property name and omitempty-ness from a
+// struct field's `json` tag, falling back to the Go field name.
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
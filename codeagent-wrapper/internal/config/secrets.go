@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ilogger "codeagent-wrapper/internal/logger"
+
+	"github.com/zalando/go-keyring"
+)
+
+// resolveSecretRef resolves raw if it is one of the recognized secret-
+// indirection forms - "${env:NAME}", "${file:path}", or
+// "${keyring:service/user}" - fetching the value from its backing store.
+// Any other string, including an empty one, is returned unchanged: plaintext
+// values already committed to ~/.codeagent/models.json keep working exactly
+// as before.
+//
+// Resolution happens lazily, on every call, rather than once at load time,
+// so ResetModelsConfigCacheForTest and the models.json hot-reload watcher
+// keep seeing the raw "${...}" string in the cached ModelsConfig, and a
+// rotated env var, file, or keyring entry takes effect on the very next
+// ResolveBackendConfig/ResolveAgentConfig call rather than requiring a
+// restart.
+//
+// Resolution fails closed: if a reference names a backing store but the
+// value can't be fetched (missing env var, unreadable file, keyring miss),
+// resolveSecretRef logs the reference itself - never the resolved value,
+// which by definition isn't available - and returns "" rather than the
+// literal placeholder string, so a typo'd reference can't leak into a
+// base_url or be used verbatim as an API key.
+func resolveSecretRef(raw string) string {
+	ref := strings.TrimSpace(raw)
+	kind, arg, ok := parseSecretRef(ref)
+	if !ok {
+		return raw
+	}
+
+	switch kind {
+	case "env":
+		if v, ok := os.LookupEnv(arg); ok {
+			return v
+		}
+		ilogger.LogError(fmt.Sprintf("config: secret reference %q: environment variable %q is not set", ref, arg))
+		return ""
+	case "file":
+		path, err := expandSecretHome(arg)
+		if err != nil {
+			ilogger.LogError(fmt.Sprintf("config: secret reference %q: %v", ref, err))
+			return ""
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- path comes from the user's own models.json
+		if err != nil {
+			ilogger.LogError(fmt.Sprintf("config: secret reference %q: %v", ref, err))
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	case "keyring":
+		service, user, found := strings.Cut(arg, "/")
+		if !found {
+			ilogger.LogError(fmt.Sprintf("config: secret reference %q: expected keyring form service/user", ref))
+			return ""
+		}
+		v, err := keyring.Get(service, user)
+		if err != nil {
+			ilogger.LogError(fmt.Sprintf("config: secret reference %q: %v", ref, err))
+			return ""
+		}
+		return v
+	default:
+		return raw
+	}
+}
+
+// parseSecretRef splits a "${kind:arg}" reference into its kind and arg. ok
+// is false for anything that isn't wrapped in "${...}", or whose kind isn't
+// one of the three recognized forms - a models.json value that merely looks
+// similar is left untouched rather than misparsed.
+func parseSecretRef(ref string) (kind, arg string, ok bool) {
+	if !strings.HasPrefix(ref, "${") || !strings.HasSuffix(ref, "}") {
+		return "", "", false
+	}
+	body := ref[2 : len(ref)-1]
+	k, a, found := strings.Cut(body, ":")
+	if !found {
+		return "", "", false
+	}
+	switch k {
+	case "env", "file", "keyring":
+		return k, a, true
+	default:
+		return "", "", false
+	}
+}
+
+// expandSecretHome expands a leading "~" or "~/" in path to the user's home
+// directory, mirroring internal/cache's expandHome for prompt files.
+func expandSecretHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	if path == "~" {
+		return home, nil
+	}
+	return filepath.Join(home, path[2:]), nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecretRef_Env(t *testing.T) {
+	t.Setenv("CODEAGENT_TEST_SECRET", "super-secret")
+
+	if got := resolveSecretRef("${env:CODEAGENT_TEST_SECRET}"); got != "super-secret" {
+		t.Errorf("resolveSecretRef(env) = %q, want %q", got, "super-secret")
+	}
+}
+
+func TestResolveSecretRef_EnvMissingFailsClosed(t *testing.T) {
+	os.Unsetenv("CODEAGENT_TEST_MISSING_SECRET")
+
+	if got := resolveSecretRef("${env:CODEAGENT_TEST_MISSING_SECRET}"); got != "" {
+		t.Errorf("resolveSecretRef(missing env) = %q, want empty string", got)
+	}
+}
+
+func TestResolveSecretRef_File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	if err := os.WriteFile(path, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resolveSecretRef("${file:" + path + "}"); got != "file-secret" {
+		t.Errorf("resolveSecretRef(file) = %q, want %q", got, "file-secret")
+	}
+}
+
+func TestResolveSecretRef_FileMissingFailsClosed(t *testing.T) {
+	if got := resolveSecretRef("${file:/no/such/path}"); got != "" {
+		t.Errorf("resolveSecretRef(missing file) = %q, want empty string", got)
+	}
+}
+
+func TestResolveSecretRef_PlaintextPassthrough(t *testing.T) {
+	if got := resolveSecretRef("plain-api-key"); got != "plain-api-key" {
+		t.Errorf("resolveSecretRef(plaintext) = %q, want unchanged", got)
+	}
+}
+
+// TestResolveBackendConfig_EnvSecretNeverLeaksRawRef asserts that a
+// "${env:...}" string written to models.json never reaches
+// ResolveBackendConfig's return values verbatim: it's either resolved to the
+// real secret or, if unresolvable, replaced with "".
+func TestResolveBackendConfig_EnvSecretNeverLeaksRawRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".codeagent")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CODEAGENT_TEST_BACKEND_KEY", "resolved-backend-key")
+
+	configContent := `{
+		"default_backend": "codex",
+		"default_model": "gpt-4o",
+		"backends": {
+			"codex": {
+				"base_url": "https://openai.example",
+				"api_key": "${env:CODEAGENT_TEST_BACKEND_KEY}"
+			}
+		}
+	}`
+	configPath := filepath.Join(configDir, "models.json")
+	if err := os.WriteFile(configPath, []byte(configContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	t.Cleanup(ResetModelsConfigCacheForTest)
+	ResetModelsConfigCacheForTest()
+
+	_, apiKey := ResolveBackendConfig("codex")
+	if apiKey != "resolved-backend-key" {
+		t.Errorf("ResolveBackendConfig(apiKey) = %q, want %q", apiKey, "resolved-backend-key")
+	}
+
+	raw := modelsConfig().Backends["codex"].APIKey
+	if raw != "${env:CODEAGENT_TEST_BACKEND_KEY}" {
+		t.Errorf("cached raw api_key = %q, want the literal reference to stay unresolved in the cache", raw)
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	ilogger "codeagent-wrapper/internal/logger"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/goccy/go-json"
+)
+
+// modelsConfigSubscribers receive the freshly-loaded ModelsConfig every time
+// ~/.codeagent/models.json is re-parsed by the watcher. Long-running
+// executors (e.g. parallel workers) can use this to react to a rotated API
+// key or a new default backend mid-session instead of restarting.
+var (
+	modelsConfigSubscribersMu sync.Mutex
+	modelsConfigSubscribers   []chan<- *ModelsConfig
+
+	modelsConfigWatcherMu sync.Mutex
+	modelsConfigWatcher   *fsnotify.Watcher
+	modelsConfigWatchDone chan struct{}
+)
+
+// SubscribeModelsConfig registers ch to receive every successfully reloaded
+// ModelsConfig. Sends are best-effort: a subscriber that can't keep up with
+// its buffer simply misses intermediate reloads, it never blocks the watcher.
+func SubscribeModelsConfig(ch chan<- *ModelsConfig) {
+	modelsConfigSubscribersMu.Lock()
+	defer modelsConfigSubscribersMu.Unlock()
+	modelsConfigSubscribers = append(modelsConfigSubscribers, ch)
+}
+
+func notifyModelsConfigSubscribers(cfg *ModelsConfig) {
+	modelsConfigSubscribersMu.Lock()
+	defer modelsConfigSubscribersMu.Unlock()
+	for _, ch := range modelsConfigSubscribers {
+		select {
+		case ch <- cfg:
+		default:
+		}
+	}
+}
+
+// SubscribeReload registers fn to be called with the freshly-loaded
+// ModelsConfig every time the watcher successfully reloads models.json. It
+// is a convenience over SubscribeModelsConfig for callers that just want a
+// side effect - e.g. a parallel executor refreshing its in-flight backend
+// selection between tasks - rather than a channel to drain themselves: each
+// call runs on its own goroutine, so a slow or blocking fn can't stall the
+// watcher loop or cause a dropped reload the way a full subscriber channel
+// would.
+func SubscribeReload(fn func(*ModelsConfig)) {
+	ch := make(chan *ModelsConfig, 1)
+	SubscribeModelsConfig(ch)
+	go func() {
+		for cfg := range ch {
+			go fn(cfg)
+		}
+	}()
+}
+
+// startModelsConfigWatcher watches ~/.codeagent for create/write/rename
+// events on models.json and atomically swaps the pointer returned by
+// modelsConfig() whenever the file changes, and resets the cached
+// PromptAllowedDirs() result when one of NewViper's config.(yaml|json|toml)
+// filenames changes. It is a no-op (logged at warn) if the watcher can't be
+// created, leaving the sync.Once-loaded snapshots in place for the lifetime
+// of the process - the old behavior.
+func startModelsConfigWatcher() {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		ilogger.LogWarn(fmt.Sprintf("models config watcher: failed to resolve home directory: %v; hot-reload disabled", err))
+		return
+	}
+	configDir := filepath.Clean(filepath.Join(home, ".codeagent"))
+	configPath := filepath.Clean(filepath.Join(configDir, "models.json"))
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		ilogger.LogWarn(fmt.Sprintf("models config watcher: failed to create fsnotify watcher: %v; hot-reload disabled", err))
+		return
+	}
+	if err := watcher.Add(configDir); err != nil {
+		// The directory may not exist yet (no models.json ever written); that's fine.
+		_ = watcher.Close()
+		return
+	}
+
+	done := make(chan struct{})
+
+	modelsConfigWatcherMu.Lock()
+	modelsConfigWatcher = watcher
+	modelsConfigWatchDone = done
+	modelsConfigWatcherMu.Unlock()
+
+	go runModelsConfigWatchLoop(watcher, done, configPath)
+}
+
+// viperConfigBasenames are the filenames NewViper's ReadInConfig search
+// picks up from ~/.codeagent; the watcher resets the prompt.allowed_dirs
+// cache (see PromptAllowedDirs) when any of them changes, so an edit takes
+// effect for the next ReadAgentPromptFile call instead of requiring a
+// restart.
+var viperConfigBasenames = map[string]bool{
+	"config.yaml": true,
+	"config.yml":  true,
+	"config.json": true,
+	"config.toml": true,
+}
+
+func runModelsConfigWatchLoop(watcher *fsnotify.Watcher, done chan struct{}, configPath string) {
+	for {
+		select {
+		case <-done:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if viperConfigBasenames[filepath.Base(filepath.Clean(event.Name))] {
+				ResetPromptAllowedDirsForTest()
+			}
+			if filepath.Clean(event.Name) != configPath {
+				continue
+			}
+			reloadModelsConfig()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			ilogger.LogWarn(fmt.Sprintf("models config watcher: %v", err))
+		}
+	}
+}
+
+// reloadModelsConfig re-parses models.json and swaps the live pointer. If the
+// file is missing or fails to parse, loadModelsConfig falls back to
+// defaultModelsConfig - but a hot-reload should keep whatever the process was
+// already running on instead of clobbering it with defaults, so we only swap
+// when the file parses cleanly.
+func reloadModelsConfig() {
+	if !modelsConfigFileParses() {
+		ilogger.LogWarn("models config watcher: reload skipped, keeping previous config")
+		return
+	}
+	cfg := loadModelsConfig()
+	modelsConfigPtr.Store(cfg)
+	notifyModelsConfigSubscribers(cfg)
+}
+
+func modelsConfigFileParses() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	configPath := filepath.Clean(filepath.Join(home, ".codeagent", "models.json"))
+	data, err := os.ReadFile(configPath) // #nosec G304 -- path is fixed under user home
+	if err != nil {
+		return false
+	}
+	var probe ModelsConfig
+	return json.Unmarshal(data, &probe) == nil
+}
+
+func stopModelsConfigWatcher() {
+	modelsConfigWatcherMu.Lock()
+	defer modelsConfigWatcherMu.Unlock()
+	if modelsConfigWatcher == nil {
+		return
+	}
+	close(modelsConfigWatchDone)
+	_ = modelsConfigWatcher.Close()
+	modelsConfigWatcher = nil
+	modelsConfigWatchDone = nil
+}
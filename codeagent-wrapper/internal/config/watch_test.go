@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSubscribeReload_InvokesOnReload(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".codeagent")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(configDir, "models.json")
+	if err := os.WriteFile(configPath, []byte(`{"default_backend":"codex","default_model":"gpt-4o"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	t.Cleanup(ResetModelsConfigCacheForTest)
+	ResetModelsConfigCacheForTest()
+
+	modelsConfig() // starts the watcher via the sync.Once in modelsConfig()
+
+	received := make(chan *ModelsConfig, 1)
+	SubscribeReload(func(cfg *ModelsConfig) {
+		select {
+		case received <- cfg:
+		default:
+		}
+	})
+
+	if err := os.WriteFile(configPath, []byte(`{"default_backend":"claude","default_model":"claude-opus-4"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case cfg := <-received:
+		if cfg.DefaultBackend != "claude" {
+			t.Errorf("reloaded DefaultBackend = %q, want %q", cfg.DefaultBackend, "claude")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for SubscribeReload to fire after models.json changed")
+	}
+}
+
+func TestModelsConfigWatcher_ViperConfigChangeResetsPromptAllowedDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".codeagent")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("USERPROFILE", tmpDir)
+	t.Cleanup(ResetModelsConfigCacheForTest)
+	t.Cleanup(ResetPromptAllowedDirsForTest)
+	ResetModelsConfigCacheForTest()
+	ResetPromptAllowedDirsForTest()
+
+	modelsConfig() // starts the watcher
+
+	if dirs := PromptAllowedDirs(); len(dirs) != 0 {
+		t.Fatalf("PromptAllowedDirs() = %v, want empty before any config file exists", dirs)
+	}
+
+	configYAML := filepath.Join(configDir, "config.yaml")
+	yamlContent := "prompt:\n  allowed_dirs:\n    - /tmp/extra-prompts\n"
+	if err := os.WriteFile(configYAML, []byte(yamlContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if dirs := PromptAllowedDirs(); len(dirs) == 1 && dirs[0] == "/tmp/extra-prompts" {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("PromptAllowedDirs() never picked up config.yaml after it was created; got %v", PromptAllowedDirs())
+}
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	cache "codeagent-wrapper/internal/cache"
+)
+
+func TestRunWithCacheHitInvokesOnMessageThenOnComplete(t *testing.T) {
+	rc, err := cache.NewResultCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewResultCache() error = %v", err)
+	}
+	defer SetSharedResultCache(rc)()
+
+	workdir := t.TempDir()
+	if out, err := exec.Command("git", "-C", workdir, "init").CombinedOutput(); err != nil {
+		t.Skipf("git init unavailable in this environment: %v: %s", err, out)
+	}
+
+	task := TaskSpec{ID: "cache-hit", Task: "do the thing", WorkDir: workdir, CachePolicy: cache.CachePolicyReadWrite}
+
+	liveCalls := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		liveCalls++
+		return TaskResult{TaskID: task.ID, ExitCode: 0, Message: "live result"}
+	}
+
+	var calls []string
+	onMessage := func() { calls = append(calls, "message") }
+	onComplete := func() { calls = append(calls, "complete") }
+
+	first := RunWithCache(task, runTaskFn, 0, onMessage, onComplete)
+	if liveCalls != 1 {
+		t.Fatalf("expected first call to miss and run live once, got %d live calls", liveCalls)
+	}
+	if first.Message != "live result" {
+		t.Fatalf("expected live result on miss, got %+v", first)
+	}
+	if got := append([]string(nil), calls...); len(got) != 1 || got[0] != "complete" {
+		t.Fatalf("expected only onComplete on a miss, got %v", got)
+	}
+
+	calls = nil
+	second := RunWithCache(task, runTaskFn, 0, onMessage, onComplete)
+	if liveCalls != 1 {
+		t.Fatalf("expected second call to hit the cache without running live again, got %d live calls", liveCalls)
+	}
+	if second.Message != "live result" {
+		t.Fatalf("expected cached result replayed, got %+v", second)
+	}
+	want := []string{"message", "complete"}
+	if len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("expected onMessage then onComplete on a hit, got %v", calls)
+	}
+}
+
+func TestRunWithCacheKeyChangesWithUncommittedEdits(t *testing.T) {
+	rc, err := cache.NewResultCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewResultCache() error = %v", err)
+	}
+	defer SetSharedResultCache(rc)()
+
+	workdir := t.TempDir()
+	run := func(args ...string) {
+		if out, err := exec.Command("git", append([]string{"-C", workdir}, args...)...).CombinedOutput(); err != nil {
+			t.Skipf("git unavailable in this environment: %v: %s", err, out)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+
+	tracked := workdir + "/tracked.txt"
+	if err := os.WriteFile(tracked, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+	run("add", "tracked.txt")
+	run("commit", "-m", "initial")
+
+	task := TaskSpec{ID: "dirty-workdir", Task: "do the thing", WorkDir: workdir, CachePolicy: cache.CachePolicyReadWrite}
+
+	liveCalls := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		liveCalls++
+		return TaskResult{TaskID: task.ID, ExitCode: 0, Message: "live result"}
+	}
+
+	RunWithCache(task, runTaskFn, 0, nil, nil)
+	if liveCalls != 1 {
+		t.Fatalf("expected first call to run live once, got %d", liveCalls)
+	}
+
+	if err := os.WriteFile(tracked, []byte("v2 - edited but not staged"), 0o644); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	RunWithCache(task, runTaskFn, 0, nil, nil)
+	if liveCalls != 2 {
+		t.Fatalf("expected uncommitted edit to a tracked file to invalidate the cache key, got %d live calls", liveCalls)
+	}
+}
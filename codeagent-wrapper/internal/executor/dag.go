@@ -0,0 +1,316 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "codeagent-wrapper/internal/executor/metrics"
+)
+
+// ExecutionTraceEvent records one task's lifecycle timestamps within a single
+// RunDAG run, for post-hoc analysis of scheduling behavior.
+type ExecutionTraceEvent struct {
+	TaskID   string    `json:"task_id"`
+	Queued   time.Time `json:"queued"`
+	Started  time.Time `json:"started,omitempty"`
+	Finished time.Time `json:"finished,omitempty"`
+	Skipped  bool      `json:"skipped,omitempty"`
+}
+
+// ExecutionTrace is the ordered list of per-task lifecycle events produced by
+// a single RunDAG call.
+type ExecutionTrace struct {
+	Events []ExecutionTraceEvent `json:"events"`
+}
+
+// BuildDAG builds the dependency graph for tasks, keyed by TaskSpec.ID. It
+// returns a descriptive error if a task depends on an unknown ID or if the
+// graph contains a cycle: Kahn's algorithm is run on a scratch copy of the
+// indegree map first, and any node that never reaches indegree zero is
+// reported as part of (or downstream of) a cycle.
+func BuildDAG(tasks []TaskSpec) (indegree map[string]int, children map[string][]string, byID map[string]*TaskSpec, err error) {
+	byID = make(map[string]*TaskSpec, len(tasks))
+	for i := range tasks {
+		byID[tasks[i].ID] = &tasks[i]
+	}
+
+	indegree = make(map[string]int, len(tasks))
+	children = make(map[string][]string, len(tasks))
+	for i := range tasks {
+		id := tasks[i].ID
+		if _, ok := indegree[id]; !ok {
+			indegree[id] = 0
+		}
+		for _, dep := range tasks[i].Dependencies {
+			if _, ok := byID[dep]; !ok {
+				return nil, nil, nil, fmt.Errorf("task %q depends on unknown task %q", id, dep)
+			}
+			children[dep] = append(children[dep], id)
+			indegree[id]++
+		}
+	}
+
+	scratch := make(map[string]int, len(indegree))
+	for id, n := range indegree {
+		scratch[id] = n
+	}
+	queue := make([]string, 0, len(scratch))
+	for id, n := range scratch {
+		if n == 0 {
+			queue = append(queue, id)
+		}
+	}
+	sort.Strings(queue)
+
+	visited := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		visited++
+
+		next := append([]string(nil), children[id]...)
+		sort.Strings(next)
+		for _, child := range next {
+			scratch[child]--
+			if scratch[child] == 0 {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	if visited != len(byID) {
+		var cyclic []string
+		for id, n := range scratch {
+			if n > 0 {
+				cyclic = append(cyclic, id)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, nil, nil, fmt.Errorf("dependency cycle detected among tasks: %s", strings.Join(cyclic, ", "))
+	}
+
+	return indegree, children, byID, nil
+}
+
+var inputTemplatePattern = regexp.MustCompile(`\{\{\s*([\w.-]+)\.(session_id|key_output|files_changed)\s*\}\}`)
+
+// templateTaskInputs substitutes {{<task-id>.<field>}} placeholders in task
+// with values from the predecessor results already collected in inputs.
+// Supported fields: session_id, key_output, files_changed (comma-joined).
+// Unresolvable placeholders are left untouched.
+func templateTaskInputs(task string, inputs map[string]TaskResult) string {
+	if len(inputs) == 0 {
+		return task
+	}
+	return inputTemplatePattern.ReplaceAllStringFunc(task, func(match string) string {
+		groups := inputTemplatePattern.FindStringSubmatch(match)
+		if groups == nil {
+			return match
+		}
+		result, ok := inputs[groups[1]]
+		if !ok {
+			return match
+		}
+		switch groups[2] {
+		case "session_id":
+			return result.SessionID
+		case "key_output":
+			return result.KeyOutput
+		case "files_changed":
+			return strings.Join(result.FilesChanged, ", ")
+		default:
+			return match
+		}
+	})
+}
+
+func collectInputs(spec *TaskSpec, results map[string]TaskResult) map[string]TaskResult {
+	if len(spec.Dependencies) == 0 {
+		return nil
+	}
+	inputs := make(map[string]TaskResult, len(spec.Dependencies))
+	for _, dep := range spec.Dependencies {
+		if r, ok := results[dep]; ok {
+			inputs[dep] = r
+		}
+	}
+	return inputs
+}
+
+// RunDAG executes cfg.Tasks honoring TaskSpec.Dependencies. Ready tasks
+// (indegree zero) run concurrently across a worker pool bounded by
+// cfg.MaxConcurrency (falling back to maxWorkers when unset); whenever a task
+// finishes, its children's indegree is decremented and any that reach zero
+// are enqueued. Downstream tasks receive their predecessors' TaskResults via
+// TaskSpec.Inputs, templated into the task text before invocation.
+//
+// With cfg.FailFast, once any task fails, every task that has not started
+// yet is recorded as skipped instead of being run; in-flight tasks still run
+// to completion. Unless cfg.ContinueOnError is
+// set, a task whose Dependencies include a failed task is recorded as
+// skipped instead of being run. The returned results follow the order of
+// cfg.Tasks; the returned ExecutionTrace records queued/started/finished
+// timestamps for every task, including skipped ones.
+func RunDAG(ctx context.Context, cfg *ParallelConfig, timeout int, maxWorkers int, runTaskFn func(TaskSpec, int) TaskResult) ([]TaskResult, *ExecutionTrace, error) {
+	indegree, children, byID, err := BuildDAG(cfg.Tasks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workers := cfg.MaxConcurrency
+	if workers <= 0 {
+		workers = maxWorkers
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	total := len(byID)
+	ready := make(chan string, total)
+	doneCh := make(chan struct{})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		remaining = make(map[string]int, total)
+		results   = make(map[string]TaskResult, total)
+		skipped   = make(map[string]bool, total)
+		trace     = &ExecutionTrace{}
+		failFast  bool
+		left      = total
+	)
+	for id, d := range indegree {
+		remaining[id] = d
+	}
+
+	markQueued := func(id string) {
+		mu.Lock()
+		trace.Events = append(trace.Events, ExecutionTraceEvent{TaskID: id, Queued: time.Now()})
+		mu.Unlock()
+		ready <- id
+	}
+
+	for id, d := range remaining {
+		if d == 0 {
+			markQueued(id)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-doneCh:
+					return
+				case id, ok := <-ready:
+					if !ok {
+						return
+					}
+					runDAGWorkerStep(runCtx, cfg, id, byID, children, runTaskFn, timeout, &mu, remaining, results, skipped, trace, &failFast, &left, doneCh, markQueued)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	cancel()
+
+	out := make([]TaskResult, 0, total)
+	for _, t := range cfg.Tasks {
+		if r, ok := results[t.ID]; ok {
+			out = append(out, r)
+		}
+	}
+	return out, trace, nil
+}
+
+func runDAGWorkerStep(
+	ctx context.Context,
+	cfg *ParallelConfig,
+	id string,
+	byID map[string]*TaskSpec,
+	children map[string][]string,
+	runTaskFn func(TaskSpec, int) TaskResult,
+	timeout int,
+	mu *sync.Mutex,
+	remaining map[string]int,
+	results map[string]TaskResult,
+	skipped map[string]bool,
+	trace *ExecutionTrace,
+	failFast *bool,
+	left *int,
+	doneCh chan struct{},
+	markQueued func(string),
+) {
+	spec := *byID[id]
+
+	mu.Lock()
+	mustSkip := *failFast
+	if !mustSkip && !cfg.ContinueOnError {
+		for _, dep := range spec.Dependencies {
+			if r, ok := results[dep]; ok && r.ExitCode != 0 {
+				mustSkip = true
+				break
+			}
+		}
+	}
+	inputs := collectInputs(&spec, results)
+	mu.Unlock()
+
+	started := time.Now()
+	var result TaskResult
+	if mustSkip {
+		result = TaskResult{TaskID: id, ExitCode: 1, Error: "skipped: dependency failed or fail-fast triggered"}
+	} else {
+		spec.Inputs = inputs
+		spec.Task = templateTaskInputs(spec.Task, inputs)
+		spec.Context = ctx
+		result = runTaskFn(spec, timeout)
+	}
+	finished := time.Now()
+
+	if !mustSkip {
+		status := "success"
+		if result.ExitCode != 0 {
+			status = "failure"
+		}
+		sink := metrics.Active()
+		sink.IncTask(spec.Backend, status)
+		sink.ObserveTaskDuration(spec.Backend, finished.Sub(started).Seconds())
+	}
+
+	mu.Lock()
+	results[id] = result
+	skipped[id] = mustSkip
+	trace.Events = append(trace.Events, ExecutionTraceEvent{TaskID: id, Started: started, Finished: finished, Skipped: mustSkip})
+	if result.ExitCode != 0 && cfg.FailFast {
+		*failFast = true
+	}
+
+	var newlyReady []string
+	for _, child := range children[id] {
+		remaining[child]--
+		if remaining[child] == 0 {
+			newlyReady = append(newlyReady, child)
+		}
+	}
+	*left--
+	allDone := *left == 0
+	mu.Unlock()
+
+	for _, child := range newlyReady {
+		markQueued(child)
+	}
+	if allDone {
+		close(doneCh)
+	}
+}
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBuildDAGDetectsCycle(t *testing.T) {
+	tasks := []TaskSpec{
+		{ID: "a", Dependencies: []string{"c"}},
+		{ID: "b", Dependencies: []string{"a"}},
+		{ID: "c", Dependencies: []string{"b"}},
+	}
+	_, _, _, err := BuildDAG(tasks)
+	if err == nil {
+		t.Fatal("expected cycle error, got nil")
+	}
+}
+
+func TestBuildDAGUnknownDependency(t *testing.T) {
+	tasks := []TaskSpec{{ID: "a", Dependencies: []string{"missing"}}}
+	_, _, _, err := BuildDAG(tasks)
+	if err == nil {
+		t.Fatal("expected unknown dependency error, got nil")
+	}
+}
+
+func TestRunDAGRespectsDependencyOrderAndSkipsOnFailure(t *testing.T) {
+	cfg := &ParallelConfig{
+		Tasks: []TaskSpec{
+			{ID: "a", Task: "do a"},
+			{ID: "b", Task: "do b with {{a.session_id}}", Dependencies: []string{"a"}},
+			{ID: "c", Task: "do c", Dependencies: []string{"b"}},
+		},
+	}
+
+	run := func(ts TaskSpec, timeout int) TaskResult {
+		if ts.ID == "a" {
+			return TaskResult{TaskID: "a", SessionID: "sess-a", ExitCode: 1}
+		}
+		if ts.ID == "b" && ts.Task != "do b with sess-a" {
+			t.Fatalf("expected templated task text, got %q", ts.Task)
+		}
+		return TaskResult{TaskID: ts.ID, ExitCode: 0}
+	}
+
+	results, trace, err := RunDAG(context.Background(), cfg, 0, 2, run)
+	if err != nil {
+		t.Fatalf("RunDAG() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	byID := map[string]TaskResult{}
+	for _, r := range results {
+		byID[r.TaskID] = r
+	}
+	if byID["b"].Error == "" {
+		t.Errorf("expected task b to be skipped after task a failed, got %+v", byID["b"])
+	}
+	if byID["c"].Error == "" {
+		t.Errorf("expected task c to be skipped transitively, got %+v", byID["c"])
+	}
+	if len(trace.Events) == 0 {
+		t.Error("expected a non-empty execution trace")
+	}
+}
+
+func TestRunDAGContinueOnErrorRunsDependents(t *testing.T) {
+	cfg := &ParallelConfig{
+		ContinueOnError: true,
+		Tasks: []TaskSpec{
+			{ID: "a", Task: "do a"},
+			{ID: "b", Task: "do b", Dependencies: []string{"a"}},
+		},
+	}
+
+	run := func(ts TaskSpec, timeout int) TaskResult {
+		if ts.ID == "a" {
+			return TaskResult{TaskID: "a", ExitCode: 1}
+		}
+		return TaskResult{TaskID: ts.ID, ExitCode: 0}
+	}
+
+	results, _, err := RunDAG(context.Background(), cfg, 0, 2, run)
+	if err != nil {
+		t.Fatalf("RunDAG() error = %v", err)
+	}
+	for _, r := range results {
+		if r.TaskID == "b" && r.ExitCode != 0 {
+			t.Errorf("expected task b to run despite failed dependency, got %+v", r)
+		}
+	}
+}
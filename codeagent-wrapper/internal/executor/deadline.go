@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ExitCodeDeadlineExceeded matches the conventional timeout(1) exit code used
+// when a task is killed for exceeding its deadline.
+const ExitCodeDeadlineExceeded = 124
+
+type deadlineEntry struct {
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	proc    processHandle
+	expired atomic.Bool
+}
+
+var deadlineRegistry sync.Map // taskID string -> *deadlineEntry
+
+// RegisterTaskDeadline wraps parent with a cancellable context and, if
+// deadline is non-zero, arms a timer that expires it. The returned context
+// should be used as TaskSpec.Context for the task's run. Callers must call
+// UnregisterTaskDeadline(taskID) once the task finishes to release the timer
+// and registry entry. SetDeadline(taskID, t) can retarget the timer for an
+// in-flight task at any point before it fires, mirroring net.Conn's
+// SetDeadline semantics.
+func RegisterTaskDeadline(taskID string, parent context.Context, deadline time.Time) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	entry := &deadlineEntry{cancel: cancel}
+	deadlineRegistry.Store(taskID, entry)
+	if !deadline.IsZero() {
+		entry.arm(deadline)
+	}
+	return ctx
+}
+
+// RegisterTaskProcess associates proc with a task previously registered via
+// RegisterTaskDeadline, so that a future deadline expiry can signal the
+// actual subprocess (SIGTERM, then relying on the task's context
+// cancellation to force-kill it after the grace period) rather than only
+// canceling its context.
+func RegisterTaskProcess(taskID string, proc processHandle) {
+	v, ok := deadlineRegistry.Load(taskID)
+	if !ok {
+		return
+	}
+	entry := v.(*deadlineEntry)
+	entry.mu.Lock()
+	entry.proc = proc
+	entry.mu.Unlock()
+}
+
+// UnregisterTaskDeadline stops any pending timer and removes taskID from the
+// registry. Safe to call even if taskID was never registered.
+func UnregisterTaskDeadline(taskID string) {
+	v, ok := deadlineRegistry.LoadAndDelete(taskID)
+	if !ok {
+		return
+	}
+	entry := v.(*deadlineEntry)
+	entry.mu.Lock()
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+	entry.mu.Unlock()
+}
+
+// SetDeadline extends or shortens the deadline for an in-flight task
+// previously registered via RegisterTaskDeadline. A zero time.Time disarms
+// the timer; calling it again before expiry replaces the pending timer
+// rather than stacking another one.
+func SetDeadline(taskID string, t time.Time) error {
+	v, ok := deadlineRegistry.Load(taskID)
+	if !ok {
+		return fmt.Errorf("no in-flight task registered with id %q", taskID)
+	}
+	v.(*deadlineEntry).arm(t)
+	return nil
+}
+
+func (e *deadlineEntry) arm(deadline time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	if deadline.IsZero() {
+		e.timer = nil
+		return
+	}
+	d := time.Until(deadline)
+	if d <= 0 {
+		go e.onExpire()
+		e.timer = nil
+		return
+	}
+	e.timer = time.AfterFunc(d, e.onExpire)
+}
+
+// onExpire sends SIGTERM to the registered process (if any), waits the
+// configured force-kill grace period, then cancels the task's context -
+// which causes the exec.CommandContext-managed subprocess to be force-killed
+// the same way an explicit timeout(1) invocation would.
+func (e *deadlineEntry) onExpire() {
+	e.expired.Store(true)
+
+	e.mu.Lock()
+	proc := e.proc
+	e.mu.Unlock()
+
+	if proc == nil {
+		e.cancel()
+		return
+	}
+
+	_ = sendTermSignal(proc)
+	grace := time.Duration(forceKillDelay.Load()) * time.Second
+	if grace <= 0 {
+		e.cancel()
+		return
+	}
+	time.AfterFunc(grace, e.cancel)
+}
+
+// effectiveDeadline returns the earlier of task.Deadline and
+// now+task.Timeout, or the zero Time if neither is set.
+func effectiveDeadline(task TaskSpec) time.Time {
+	deadline := task.Deadline
+	if task.Timeout > 0 {
+		fromTimeout := time.Now().Add(task.Timeout)
+		if deadline.IsZero() || fromTimeout.Before(deadline) {
+			deadline = fromTimeout
+		}
+	}
+	return deadline
+}
+
+// RunWithDeadline runs fn with a context derived from task.Timeout/
+// task.Deadline (the earlier of the two wins) registered under task.ID so
+// SetDeadline can retarget it mid-flight, and normalizes the result to
+// ExitCode=ExitCodeDeadlineExceeded, Error="deadline exceeded" when the
+// deadline - rather than normal completion or the parent context - is what
+// ended the task.
+func RunWithDeadline(parent context.Context, task TaskSpec, fn func(context.Context) TaskResult) TaskResult {
+	ctx := RegisterTaskDeadline(task.ID, parent, effectiveDeadline(task))
+	entryVal, _ := deadlineRegistry.Load(task.ID)
+	entry, _ := entryVal.(*deadlineEntry)
+	defer UnregisterTaskDeadline(task.ID)
+
+	result := fn(ctx)
+	if entry != nil && entry.expired.Load() {
+		result.ExitCode = ExitCodeDeadlineExceeded
+		result.Error = "deadline exceeded"
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunWithDeadlineExpiresTask(t *testing.T) {
+	task := TaskSpec{ID: "deadline-expire", Timeout: 10 * time.Millisecond}
+
+	result := RunWithDeadline(context.Background(), task, func(ctx context.Context) TaskResult {
+		<-ctx.Done()
+		return TaskResult{TaskID: task.ID, ExitCode: -1}
+	})
+
+	if result.ExitCode != ExitCodeDeadlineExceeded {
+		t.Fatalf("ExitCode = %d, want %d", result.ExitCode, ExitCodeDeadlineExceeded)
+	}
+	if result.Error != "deadline exceeded" {
+		t.Fatalf("Error = %q, want %q", result.Error, "deadline exceeded")
+	}
+}
+
+func TestRunWithDeadlineNormalCompletionUnaffected(t *testing.T) {
+	task := TaskSpec{ID: "deadline-normal", Timeout: time.Second}
+
+	result := RunWithDeadline(context.Background(), task, func(ctx context.Context) TaskResult {
+		return TaskResult{TaskID: task.ID, ExitCode: 0}
+	})
+
+	if result.ExitCode != 0 || result.Error != "" {
+		t.Fatalf("expected untouched result, got %+v", result)
+	}
+	if _, ok := deadlineRegistry.Load(task.ID); ok {
+		t.Fatal("expected deadline registry entry to be removed after completion")
+	}
+}
+
+func TestSetDeadlineRetargetsInFlightTask(t *testing.T) {
+	taskID := "deadline-retarget"
+	ctx := RegisterTaskDeadline(taskID, context.Background(), time.Now().Add(time.Hour))
+	defer UnregisterTaskDeadline(taskID)
+
+	if err := SetDeadline(taskID, time.Now().Add(10*time.Millisecond)); err != nil {
+		t.Fatalf("SetDeadline() error = %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected context to be canceled after shortened deadline")
+	}
+
+	if err := SetDeadline("no-such-task", time.Now()); err == nil {
+		t.Fatal("expected error for unregistered task id")
+	}
+}
@@ -1,6 +1,10 @@
 package executor
 
-import "bytes"
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
 
 type logWriter struct {
 	prefix  string
@@ -93,32 +97,137 @@ func (lw *logWriter) writeLimited(p []byte) {
 	lw.dropped = true
 }
 
+// defaultTailBufferMaxLines and defaultTailBufferMaxBytes bound a tailBuffer
+// constructed with newTailBuffer(0, 0): whichever limit is hit first evicts
+// the oldest surviving line.
+const (
+	defaultTailBufferMaxLines = 200
+	defaultTailBufferMaxBytes = 64 * 1024
+)
+
+// tailBuffer keeps the most recent complete lines written to it as a
+// fixed-capacity ring, evicting the oldest line once either maxLines or
+// maxBytes (summed over the surviving lines) is exceeded. Unlike a plain
+// last-N-bytes tail, complete lines are kept or evicted whole: Write splits
+// its input on '\n', holding any trailing partial line in pending until the
+// next Write supplies its newline, or until Lines/LastN/Snapshot is asked
+// for the buffer's current contents. The one exception is a pending line
+// that alone grows past maxBytes before its newline arrives: its oldest
+// bytes are dropped (see truncatePending), cutting only at a UTF-8 rune
+// boundary.
 type tailBuffer struct {
-	limit int
-	data  []byte
+	maxLines int
+	maxBytes int
+
+	lines        []string
+	lineBytes    int
+	pending      []byte
+	totalBytes   int64
+	droppedLines int
+}
+
+func newTailBuffer(maxLines, maxBytes int) *tailBuffer {
+	if maxLines <= 0 {
+		maxLines = defaultTailBufferMaxLines
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultTailBufferMaxBytes
+	}
+	return &tailBuffer{maxLines: maxLines, maxBytes: maxBytes}
 }
 
 func (b *tailBuffer) Write(p []byte) (int, error) {
-	if b.limit <= 0 {
-		return len(p), nil
+	total := len(p)
+	b.totalBytes += int64(total)
+
+	for len(p) > 0 {
+		idx := bytes.IndexByte(p, '\n')
+		if idx < 0 {
+			b.pending = append(b.pending, p...)
+			b.truncatePending()
+			break
+		}
+		b.pending = append(b.pending, p[:idx]...)
+		b.pushLine(string(b.pending))
+		b.pending = b.pending[:0]
+		p = p[idx+1:]
 	}
+	return total, nil
+}
 
-	if len(p) >= b.limit {
-		b.data = append(b.data[:0], p[len(p)-b.limit:]...)
-		return len(p), nil
+// truncatePending drops bytes from the front of a pending line that has
+// grown past maxBytes on its own (no newline seen yet), advancing to the
+// next UTF-8 rune boundary rather than slicing through one.
+func (b *tailBuffer) truncatePending() {
+	if len(b.pending) <= b.maxBytes {
+		return
 	}
+	cut := len(b.pending) - b.maxBytes
+	for cut < len(b.pending) && !utf8.RuneStart(b.pending[cut]) {
+		cut++
+	}
+	b.pending = append([]byte{}, b.pending[cut:]...)
+}
 
-	total := len(b.data) + len(p)
-	if total <= b.limit {
-		b.data = append(b.data, p...)
-		return len(p), nil
+// pushLine appends line as the newest entry and evicts the oldest entries
+// until both the line count and the summed line-byte budget are back
+// within maxLines/maxBytes.
+func (b *tailBuffer) pushLine(line string) {
+	b.lines = append(b.lines, line)
+	b.lineBytes += len(line)
+
+	for len(b.lines) > 0 && (len(b.lines) > b.maxLines || b.lineBytes > b.maxBytes) {
+		b.lineBytes -= len(b.lines[0])
+		b.lines = b.lines[1:]
+		b.droppedLines++
+	}
+}
+
+// Lines returns every complete line currently held, oldest first, plus a
+// trailing partial line (one with no terminating '\n' yet) if any bytes are
+// pending.
+func (b *tailBuffer) Lines() []string {
+	if len(b.pending) == 0 {
+		out := make([]string, len(b.lines))
+		copy(out, b.lines)
+		return out
 	}
+	out := make([]string, len(b.lines)+1)
+	copy(out, b.lines)
+	out[len(b.lines)] = string(b.pending)
+	return out
+}
+
+// LastN returns the last n lines from Lines(), or every line held if there
+// are fewer than n.
+func (b *tailBuffer) LastN(n int) []string {
+	all := b.Lines()
+	if n <= 0 || n >= len(all) {
+		return all
+	}
+	return all[len(all)-n:]
+}
 
-	overflow := total - b.limit
-	b.data = append(b.data[overflow:], p...)
-	return len(p), nil
+// TailSnapshot is a point-in-time view of a tailBuffer's contents plus the
+// counters a failing-task diagnostic needs to caveat a truncated tail, e.g.
+// "(12 earlier lines dropped)".
+type TailSnapshot struct {
+	Lines        []string
+	TotalBytes   int64
+	DroppedLines int
+}
+
+// Snapshot returns a TailSnapshot of b's current contents.
+func (b *tailBuffer) Snapshot() TailSnapshot {
+	return TailSnapshot{
+		Lines:        b.Lines(),
+		TotalBytes:   b.totalBytes,
+		DroppedLines: b.droppedLines,
+	}
 }
 
+// String joins every line currently held with '\n', for callers that just
+// want the tail as plain text.
 func (b *tailBuffer) String() string {
-	return string(b.data)
+	return strings.Join(b.Lines(), "\n")
 }
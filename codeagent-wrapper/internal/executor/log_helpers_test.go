@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTailBufferLastNAndLineEviction(t *testing.T) {
+	b := newTailBuffer(3, 0)
+	for i := 1; i <= 5; i++ {
+		b.Write([]byte(strings.Repeat("x", 1) + "\n"))
+		_ = i
+	}
+	lines := b.Lines()
+	if len(lines) != 3 {
+		t.Fatalf("Lines() len = %d, want 3", len(lines))
+	}
+	snap := b.Snapshot()
+	if snap.DroppedLines != 2 {
+		t.Fatalf("DroppedLines = %d, want 2", snap.DroppedLines)
+	}
+	if snap.TotalBytes != 10 {
+		t.Fatalf("TotalBytes = %d, want 10", snap.TotalBytes)
+	}
+}
+
+func TestTailBufferByteEviction(t *testing.T) {
+	b := newTailBuffer(100, 5)
+	b.Write([]byte("abc\n"))
+	b.Write([]byte("defgh\n"))
+	lines := b.Lines()
+	if len(lines) != 1 || lines[0] != "defgh" {
+		t.Fatalf("Lines() = %v, want [defgh]", lines)
+	}
+	if b.Snapshot().DroppedLines != 1 {
+		t.Fatalf("DroppedLines = %d, want 1", b.Snapshot().DroppedLines)
+	}
+}
+
+func TestTailBufferPendingLineIncludedInLastN(t *testing.T) {
+	b := newTailBuffer(10, 0)
+	b.Write([]byte("first\n"))
+	b.Write([]byte("second-no-newline-yet"))
+
+	last := b.LastN(2)
+	if len(last) != 2 || last[0] != "first" || last[1] != "second-no-newline-yet" {
+		t.Fatalf("LastN(2) = %v, want [first second-no-newline-yet]", last)
+	}
+}
+
+func TestTailBufferTruncatePendingPreservesUTF8Boundary(t *testing.T) {
+	b := newTailBuffer(10, 4)
+	// "é" is two bytes in UTF-8; write enough to force truncation right at
+	// the boundary between "é" and "x" to confirm we never split the rune.
+	b.Write([]byte("éxxxx"))
+
+	last := b.LastN(1)
+	if len(last) != 1 {
+		t.Fatalf("LastN(1) = %v, want one pending line", last)
+	}
+	if !utf8.ValidString(last[0]) {
+		t.Fatalf("LastN(1)[0] = %q is not valid UTF-8", last[0])
+	}
+}
+
+func TestTailBufferStringJoinsWithNewlines(t *testing.T) {
+	b := newTailBuffer(10, 0)
+	b.Write([]byte("one\ntwo\n"))
+	if got, want := b.String(), "one\ntwo"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+// Package metrics provides a pluggable telemetry sink for the parallel
+// executor and stream parser, defaulting to a no-op implementation so
+// instrumentation costs nothing when metrics are not enabled.
+package metrics
+
+import "sync"
+
+// Sink records executor/parser telemetry. Implementations must be safe for
+// concurrent use, since tasks and stream parsing run concurrently in
+// parallel mode.
+type Sink interface {
+	// IncTask records a completed task attempt for backend, with status
+	// typically "success" or "failure".
+	IncTask(backend, status string)
+	// ObserveTaskDuration records how long a task attempt took, in seconds.
+	ObserveTaskDuration(backend string, seconds float64)
+	// IncStreamEvent records one parsed stream event of eventType for backend.
+	IncStreamEvent(backend, eventType string)
+	// ObserveJSONLineBytes records the size, in bytes, of a line read from a
+	// backend's JSON output stream.
+	ObserveJSONLineBytes(bytes int)
+	// IncOverlongLine records a JSON line skipped for exceeding the byte
+	// limit, for backend.
+	IncOverlongLine(backend string)
+}
+
+type noopSink struct{}
+
+func (noopSink) IncTask(string, string)              {}
+func (noopSink) ObserveTaskDuration(string, float64) {}
+func (noopSink) IncStreamEvent(string, string)       {}
+func (noopSink) ObserveJSONLineBytes(int)            {}
+func (noopSink) IncOverlongLine(string)              {}
+
+var (
+	activeSinkMu sync.RWMutex
+	activeSink   Sink = noopSink{}
+)
+
+// Active returns the process-wide Sink. It defaults to a no-op sink with
+// zero runtime overhead until SetSink installs a real one (e.g. once
+// --metrics-addr is parsed).
+func Active() Sink {
+	activeSinkMu.RLock()
+	defer activeSinkMu.RUnlock()
+	return activeSink
+}
+
+// SetSink installs sink as the process-wide Sink. Passing nil restores the
+// no-op default. An interface-typed atomic.Value can't hold this - its
+// concrete type changes with every real sink implementation, which panics on
+// Store - so this uses a plain mutex-guarded variable instead.
+func SetSink(sink Sink) {
+	if sink == nil {
+		sink = noopSink{}
+	}
+	activeSinkMu.Lock()
+	defer activeSinkMu.Unlock()
+	activeSink = sink
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import "testing"
+
+type fakeSink struct {
+	tasks  []string
+	events []string
+}
+
+func (f *fakeSink) IncTask(backend, status string) { f.tasks = append(f.tasks, backend+":"+status) }
+func (f *fakeSink) ObserveTaskDuration(string, float64) {}
+func (f *fakeSink) IncStreamEvent(backend, eventType string) {
+	f.events = append(f.events, backend+":"+eventType)
+}
+func (f *fakeSink) ObserveJSONLineBytes(int) {}
+func (f *fakeSink) IncOverlongLine(string)   {}
+
+func TestActiveDefaultsToNoop(t *testing.T) {
+	SetSink(nil)
+	if _, ok := Active().(noopSink); !ok {
+		t.Fatalf("Active() = %T, want noopSink", Active())
+	}
+}
+
+func TestSetSinkInstallsAndRestores(t *testing.T) {
+	defer SetSink(nil)
+
+	f := &fakeSink{}
+	SetSink(f)
+
+	Active().IncTask("codex", "success")
+	Active().IncStreamEvent("codex", "item.completed")
+
+	if len(f.tasks) != 1 || f.tasks[0] != "codex:success" {
+		t.Fatalf("tasks = %v, want [codex:success]", f.tasks)
+	}
+	if len(f.events) != 1 || f.events[0] != "codex:item.completed" {
+		t.Fatalf("events = %v, want [codex:item.completed]", f.events)
+	}
+
+	SetSink(nil)
+	if _, ok := Active().(noopSink); !ok {
+		t.Fatal("expected SetSink(nil) to restore noopSink")
+	}
+}
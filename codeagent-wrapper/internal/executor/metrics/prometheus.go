@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusSink implements Sink using prometheus/client_golang, registering
+// its collectors with a private registry so multiple sinks (e.g. in tests)
+// don't collide on the global default registerer.
+type PrometheusSink struct {
+	registry      *prometheus.Registry
+	tasksTotal    *prometheus.CounterVec
+	taskDuration  *prometheus.HistogramVec
+	streamEvents  *prometheus.CounterVec
+	jsonLineBytes prometheus.Histogram
+	overlongLines *prometheus.CounterVec
+}
+
+// NewPrometheusSink creates a PrometheusSink with its own registry and
+// registers the codeagent_* collectors described in the parallel executor
+// telemetry design.
+func NewPrometheusSink() *PrometheusSink {
+	s := &PrometheusSink{
+		registry: prometheus.NewRegistry(),
+		tasksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "codeagent_tasks_total",
+			Help: "Total number of executed tasks, by backend and outcome status.",
+		}, []string{"backend", "status"}),
+		taskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "codeagent_task_duration_seconds",
+			Help:    "Task execution duration in seconds, by backend.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"backend"}),
+		streamEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "codeagent_stream_events_total",
+			Help: "Total number of parsed backend stream events, by backend and event type.",
+		}, []string{"backend", "type"}),
+		jsonLineBytes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "codeagent_json_line_bytes",
+			Help:    "Size in bytes of JSON lines read from backend output streams.",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		}),
+		overlongLines: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "codeagent_overlong_lines_total",
+			Help: "Total number of JSON lines skipped for exceeding the byte limit, by backend.",
+		}, []string{"backend"}),
+	}
+	s.registry.MustRegister(s.tasksTotal, s.taskDuration, s.streamEvents, s.jsonLineBytes, s.overlongLines)
+	return s
+}
+
+func (s *PrometheusSink) IncTask(backend, status string) {
+	s.tasksTotal.WithLabelValues(backend, status).Inc()
+}
+
+func (s *PrometheusSink) ObserveTaskDuration(backend string, seconds float64) {
+	s.taskDuration.WithLabelValues(backend).Observe(seconds)
+}
+
+func (s *PrometheusSink) IncStreamEvent(backend, eventType string) {
+	s.streamEvents.WithLabelValues(backend, eventType).Inc()
+}
+
+func (s *PrometheusSink) ObserveJSONLineBytes(n int) {
+	s.jsonLineBytes.Observe(float64(n))
+}
+
+func (s *PrometheusSink) IncOverlongLine(backend string) {
+	s.overlongLines.WithLabelValues(backend).Inc()
+}
+
+// Handler returns an http.Handler serving s's collectors in the Prometheus
+// exposition format, suitable for mounting at /metrics.
+func (s *PrometheusSink) Handler() http.Handler {
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{})
+}
+
+// Serve starts an HTTP server bound to addr exposing s at /metrics. It
+// blocks until the server stops; callers typically run it in a goroutine.
+func Serve(addr string, s *PrometheusSink) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.Handler())
+	return http.ListenAndServe(addr, mux) // #nosec G114 -- local/ops-facing metrics endpoint, no external timeout requirement
+}
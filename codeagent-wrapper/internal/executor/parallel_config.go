@@ -3,6 +3,7 @@ package executor
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 
 	config "codeagent-wrapper/internal/config"
@@ -82,54 +83,150 @@ func ParseParallelConfig(data []byte) (*ParallelConfig, error) {
 						task.Dependencies = append(task.Dependencies, dep)
 					}
 				}
+			case "cpu_shares":
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("task block #%d has invalid cpu_shares: %w", taskIndex, err)
+				}
+				task.Resources.CPUShares = n
+			case "cpu_quota":
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("task block #%d has invalid cpu_quota: %w", taskIndex, err)
+				}
+				task.Resources.CPUQuota = n
+			case "memory_limit":
+				n, err := parseResourceByteSize(value)
+				if err != nil {
+					return nil, fmt.Errorf("task block #%d has invalid memory_limit: %w", taskIndex, err)
+				}
+				task.Resources.MemoryLimit = n
+			case "memory_reservation":
+				n, err := parseResourceByteSize(value)
+				if err != nil {
+					return nil, fmt.Errorf("task block #%d has invalid memory_reservation: %w", taskIndex, err)
+				}
+				task.Resources.MemoryReservation = n
+			case "blkio_weight":
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("task block #%d has invalid blkio_weight: %w", taskIndex, err)
+				}
+				task.Resources.BlkioWeight = n
+			case "pids_max":
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("task block #%d has invalid pids_max: %w", taskIndex, err)
+				}
+				task.Resources.PIDsMax = n
 			}
 		}
 
-		if task.Mode == "" {
-			task.Mode = "new"
+		if err := finalizeTask(&task, taskIndex, agentSpecified, content, seen); err != nil {
+			return nil, err
 		}
 
-		if agentSpecified {
-			if strings.TrimSpace(task.Agent) == "" {
-				return nil, fmt.Errorf("task block #%d has empty agent field", taskIndex)
-			}
-			if err := config.ValidateAgentName(task.Agent); err != nil {
-				return nil, fmt.Errorf("task block #%d invalid agent name: %w", taskIndex, err)
-			}
-			backend, model, promptFile, reasoning, _, _, _ := config.ResolveAgentConfig(task.Agent)
-			if task.Backend == "" {
-				task.Backend = backend
-			}
-			if task.Model == "" {
-				task.Model = model
-			}
-			if task.ReasoningEffort == "" {
-				task.ReasoningEffort = reasoning
-			}
-			task.PromptFile = promptFile
-		}
+		cfg.Tasks = append(cfg.Tasks, task)
+		seen[task.ID] = struct{}{}
+	}
+
+	if len(cfg.Tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+
+	return &cfg, nil
+}
 
-		if task.ID == "" {
-			return nil, fmt.Errorf("task block #%d missing id field", taskIndex)
+// finalizeTask applies the validation and defaulting shared by every
+// ParallelConfig source format (legacy ---TASK--- text, YAML, JSON): agent
+// resolution via config.ResolveAgentConfig, the new/resume Mode default,
+// required-field checks, duplicate-id detection, and resource-limit
+// validation. It mutates task in place; callers append it to cfg.Tasks and
+// mark it in seen themselves once finalizeTask succeeds.
+func finalizeTask(task *TaskSpec, taskIndex int, agentSpecified bool, content string, seen map[string]struct{}) error {
+	if task.Mode == "" {
+		task.Mode = "new"
+	}
+
+	if agentSpecified {
+		if strings.TrimSpace(task.Agent) == "" {
+			return fmt.Errorf("task block #%d has empty agent field", taskIndex)
+		}
+		if err := config.ValidateAgentName(task.Agent); err != nil {
+			return fmt.Errorf("task block #%d invalid agent name: %w", taskIndex, err)
 		}
-		if content == "" {
-			return nil, fmt.Errorf("task block #%d (%q) missing content", taskIndex, task.ID)
+		backend, model, promptFile, reasoning, _, _, _, budget := config.ResolveAgentConfig(task.Agent)
+		if task.Backend == "" {
+			task.Backend = backend
 		}
-		if task.Mode == "resume" && strings.TrimSpace(task.SessionID) == "" {
-			return nil, fmt.Errorf("task block #%d (%q) has empty session_id", taskIndex, task.ID)
+		if task.Model == "" {
+			task.Model = model
 		}
-		if _, exists := seen[task.ID]; exists {
-			return nil, fmt.Errorf("task block #%d has duplicate id: %s", taskIndex, task.ID)
+		if task.ReasoningEffort == "" {
+			task.ReasoningEffort = reasoning
 		}
+		task.PromptFile = promptFile
+		if task.ResourceBudget.IsZero() {
+			task.ResourceBudget = budget
+		}
+	}
 
-		task.Task = content
-		cfg.Tasks = append(cfg.Tasks, task)
-		seen[task.ID] = struct{}{}
+	if task.ID == "" {
+		return fmt.Errorf("task block #%d missing id field", taskIndex)
+	}
+	if content == "" {
+		return fmt.Errorf("task block #%d (%q) missing content", taskIndex, task.ID)
+	}
+	if task.Mode == "resume" && strings.TrimSpace(task.SessionID) == "" {
+		return fmt.Errorf("task block #%d (%q) has empty session_id", taskIndex, task.ID)
+	}
+	if _, exists := seen[task.ID]; exists {
+		return fmt.Errorf("task block #%d has duplicate id: %s", taskIndex, task.ID)
+	}
+	if err := task.Resources.Validate(); err != nil {
+		return fmt.Errorf("task block #%d (%q) has invalid resources: %w", taskIndex, task.ID, err)
 	}
 
-	if len(cfg.Tasks) == 0 {
-		return nil, fmt.Errorf("no tasks found")
+	task.Task = content
+	return nil
+}
+
+// parseResourceByteSize parses sizes like "512MB", "2GB", or a bare byte
+// count ("1048576") for the memory_limit/memory_reservation task fields.
+func parseResourceByteSize(raw string) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
 	}
+	lower := strings.ToLower(raw)
 
-	return &cfg, nil
+	i := 0
+	for i < len(lower) && (lower[i] == '.' || (lower[i] >= '0' && lower[i] <= '9')) {
+		i++
+	}
+	if i == 0 {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+	value, err := strconv.ParseFloat(lower[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+
+	unit := strings.TrimSpace(lower[i:])
+	var multiplier int64 = 1
+	switch unit {
+	case "", "b":
+		multiplier = 1
+	case "kb":
+		multiplier = 1 << 10
+	case "mb":
+		multiplier = 1 << 20
+	case "gb":
+		multiplier = 1 << 30
+	case "tb":
+		multiplier = 1 << 40
+	default:
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+	return int64(value * float64(multiplier)), nil
 }
@@ -0,0 +1,158 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+)
+
+// structuredParallelConfig is the YAML/JSON document shape accepted by
+// ParseParallelConfigYAML and ParseParallelConfigJSON: the same top-level
+// knobs as ParallelConfig, with Tasks described as structuredTaskSpec
+// instead of the bespoke ---TASK---/---CONTENT--- text blocks.
+type structuredParallelConfig struct {
+	Tasks           []structuredTaskSpec `yaml:"tasks" json:"tasks"`
+	Backend         string               `yaml:"backend,omitempty" json:"backend,omitempty"`
+	MaxConcurrency  int                  `yaml:"max_concurrency,omitempty" json:"max_concurrency,omitempty"`
+	FailFast        bool                 `yaml:"fail_fast,omitempty" json:"fail_fast,omitempty"`
+	ContinueOnError bool                 `yaml:"continue_on_error,omitempty" json:"continue_on_error,omitempty"`
+}
+
+type structuredTaskSpec struct {
+	ID                string   `yaml:"id" json:"id"`
+	WorkDir           string   `yaml:"workdir,omitempty" json:"workdir,omitempty"`
+	Backend           string   `yaml:"backend,omitempty" json:"backend,omitempty"`
+	Model             string   `yaml:"model,omitempty" json:"model,omitempty"`
+	Agent             string   `yaml:"agent,omitempty" json:"agent,omitempty"`
+	ReasoningEffort   string   `yaml:"reasoning_effort,omitempty" json:"reasoning_effort,omitempty"`
+	SkipPermissions   bool     `yaml:"skip_permissions,omitempty" json:"skip_permissions,omitempty"`
+	SessionID         string   `yaml:"session_id,omitempty" json:"session_id,omitempty"`
+	Dependencies      []string `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
+	Content           string   `yaml:"content" json:"content"`
+	CPUShares         int64    `yaml:"cpu_shares,omitempty" json:"cpu_shares,omitempty"`
+	CPUQuota          int64    `yaml:"cpu_quota,omitempty" json:"cpu_quota,omitempty"`
+	MemoryLimit       string   `yaml:"memory_limit,omitempty" json:"memory_limit,omitempty"`
+	MemoryReservation string   `yaml:"memory_reservation,omitempty" json:"memory_reservation,omitempty"`
+	BlkioWeight       int64    `yaml:"blkio_weight,omitempty" json:"blkio_weight,omitempty"`
+	PIDsMax           int64    `yaml:"pids_max,omitempty" json:"pids_max,omitempty"`
+}
+
+// ParseParallelConfigYAML parses a structured YAML parallel config document
+// (see structuredParallelConfig), sharing validation and defaulting with
+// ParseParallelConfig via finalizeTask.
+func ParseParallelConfigYAML(data []byte) (*ParallelConfig, error) {
+	var doc structuredParallelConfig
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML parallel config: %w", err)
+	}
+	return buildParallelConfigFromStructured(doc)
+}
+
+// ParseParallelConfigJSON parses a structured JSON parallel config document
+// (see structuredParallelConfig), sharing validation and defaulting with
+// ParseParallelConfig via finalizeTask.
+func ParseParallelConfigJSON(data []byte) (*ParallelConfig, error) {
+	var doc structuredParallelConfig
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON parallel config: %w", err)
+	}
+	return buildParallelConfigFromStructured(doc)
+}
+
+func buildParallelConfigFromStructured(doc structuredParallelConfig) (*ParallelConfig, error) {
+	cfg := ParallelConfig{
+		GlobalBackend:   doc.Backend,
+		MaxConcurrency:  doc.MaxConcurrency,
+		FailFast:        doc.FailFast,
+		ContinueOnError: doc.ContinueOnError,
+	}
+	seen := make(map[string]struct{})
+
+	for i, t := range doc.Tasks {
+		taskIndex := i + 1
+
+		if t.WorkDir == "-" {
+			return nil, fmt.Errorf("task block #%d has invalid workdir: '-' is not a valid directory path", taskIndex)
+		}
+		workdir := t.WorkDir
+		if workdir == "" {
+			workdir = defaultWorkdir
+		}
+
+		task := TaskSpec{
+			ID:              t.ID,
+			WorkDir:         workdir,
+			Backend:         t.Backend,
+			Model:           t.Model,
+			Agent:           t.Agent,
+			ReasoningEffort: t.ReasoningEffort,
+			SkipPermissions: t.SkipPermissions,
+			SessionID:       t.SessionID,
+			Dependencies:    t.Dependencies,
+		}
+		if task.SessionID != "" {
+			task.Mode = "resume"
+		}
+
+		task.Resources.CPUShares = t.CPUShares
+		task.Resources.CPUQuota = t.CPUQuota
+		task.Resources.BlkioWeight = t.BlkioWeight
+		task.Resources.PIDsMax = t.PIDsMax
+		if t.MemoryLimit != "" {
+			n, err := parseResourceByteSize(t.MemoryLimit)
+			if err != nil {
+				return nil, fmt.Errorf("task block #%d has invalid memory_limit: %w", taskIndex, err)
+			}
+			task.Resources.MemoryLimit = n
+		}
+		if t.MemoryReservation != "" {
+			n, err := parseResourceByteSize(t.MemoryReservation)
+			if err != nil {
+				return nil, fmt.Errorf("task block #%d has invalid memory_reservation: %w", taskIndex, err)
+			}
+			task.Resources.MemoryReservation = n
+		}
+
+		agentSpecified := strings.TrimSpace(t.Agent) != ""
+		content := strings.TrimSpace(t.Content)
+
+		if err := finalizeTask(&task, taskIndex, agentSpecified, content, seen); err != nil {
+			return nil, err
+		}
+
+		cfg.Tasks = append(cfg.Tasks, task)
+		seen[task.ID] = struct{}{}
+	}
+
+	if len(cfg.Tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+
+	return &cfg, nil
+}
+
+// ParseParallelConfigAuto sniffs data's format and routes to the matching
+// parser: a leading '{' or '[' means JSON, a leading "tasks:" or "- id:"
+// line (after trimming blank lines) means YAML, and anything else falls
+// back to the legacy ---TASK---/---CONTENT--- text format.
+func ParseParallelConfigAuto(data []byte) (*ParallelConfig, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("parallel config is empty")
+	}
+
+	switch trimmed[0] {
+	case '{', '[':
+		return ParseParallelConfigJSON(trimmed)
+	}
+
+	firstLine := strings.TrimSpace(strings.SplitN(string(trimmed), "\n", 2)[0])
+	if strings.HasPrefix(firstLine, "tasks:") || strings.HasPrefix(firstLine, "- id:") {
+		return ParseParallelConfigYAML(trimmed)
+	}
+
+	return ParseParallelConfig(trimmed)
+}
@@ -0,0 +1,79 @@
+package executor
+
+import "testing"
+
+func TestParseParallelConfigJSON(t *testing.T) {
+	input := `{
+		"tasks": [
+			{"id": "t1", "backend": "codex", "content": "do the thing"},
+			{"id": "t2", "dependencies": ["t1"], "content": "depends on t1\n---TASK---\nstill one task"}
+		]
+	}`
+
+	cfg, err := ParseParallelConfigJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseParallelConfigJSON() error = %v", err)
+	}
+	if len(cfg.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(cfg.Tasks))
+	}
+	if cfg.Tasks[1].Task == "" || cfg.Tasks[1].Dependencies[0] != "t1" {
+		t.Fatalf("unexpected second task: %+v", cfg.Tasks[1])
+	}
+}
+
+func TestParseParallelConfigYAML(t *testing.T) {
+	input := "tasks:\n  - id: t1\n    backend: codex\n    content: do the thing\n  - id: t2\n    session_id: abc\n    content: resume please\n"
+
+	cfg, err := ParseParallelConfigYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseParallelConfigYAML() error = %v", err)
+	}
+	if len(cfg.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(cfg.Tasks))
+	}
+	if cfg.Tasks[1].Mode != "resume" || cfg.Tasks[1].SessionID != "abc" {
+		t.Fatalf("expected second task to resume session abc, got %+v", cfg.Tasks[1])
+	}
+}
+
+func TestParseParallelConfigYAMLDefaultsModeToNewWithoutSessionID(t *testing.T) {
+	input := "tasks:\n  - id: t1\n    content: oops\n"
+	cfg, err := ParseParallelConfigYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Tasks[0].Mode != "new" {
+		t.Fatalf("expected default mode new without session_id, got %q", cfg.Tasks[0].Mode)
+	}
+}
+
+func TestParseParallelConfigAutoSniffsFormat(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{"json", `{"tasks":[{"id":"t1","content":"hi"}]}`},
+		{"yaml", "tasks:\n  - id: t1\n    content: hi\n"},
+		{"legacy", "---TASK---\nid: t1\n---CONTENT---\nhi"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := ParseParallelConfigAuto([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("ParseParallelConfigAuto() error = %v", err)
+			}
+			if len(cfg.Tasks) != 1 || cfg.Tasks[0].ID != "t1" {
+				t.Fatalf("unexpected config: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestParseParallelConfigJSONDuplicateID(t *testing.T) {
+	input := `{"tasks":[{"id":"t1","content":"a"},{"id":"t1","content":"b"}]}`
+	if _, err := ParseParallelConfigJSON([]byte(input)); err == nil {
+		t.Fatalf("expected duplicate id error")
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	cache "codeagent-wrapper/internal/cache"
+	config "codeagent-wrapper/internal/config"
 )
 
 func ReadAgentPromptFile(path string, allowOutsideClaudeDir bool) (string, error) {
@@ -43,6 +46,7 @@ func ReadAgentPromptFile(path string, allowOutsideClaudeDir bool) (string, error
 			filepath.Clean(filepath.Join(home, ".claude")),
 			filepath.Clean(filepath.Join(home, ".codeagent", "agents")),
 		}
+		allowedDirs = append(allowedDirs, resolveUserAllowedDirs(home, allowOutsideClaudeDir)...)
 		for i := range allowedDirs {
 			allowedAbs, err := filepath.Abs(allowedDirs[i])
 			if err == nil {
@@ -50,22 +54,6 @@ func ReadAgentPromptFile(path string, allowOutsideClaudeDir bool) (string, error
 			}
 		}
 
-		isWithinDir := func(path, dir string) bool {
-			rel, err := filepath.Rel(dir, path)
-			if err != nil {
-				return false
-			}
-			rel = filepath.Clean(rel)
-			if rel == "." {
-				return true
-			}
-			if rel == ".." {
-				return false
-			}
-			prefix := ".." + string(os.PathSeparator)
-			return !strings.HasPrefix(rel, prefix)
-		}
-
 		if !allowOutsideClaudeDir {
 			withinAllowed := false
 			for _, dir := range allowedDirs {
@@ -118,7 +106,7 @@ func ReadAgentPromptFile(path string, allowOutsideClaudeDir bool) (string, error
 		}
 	}
 
-	data, err := os.ReadFile(absPath)
+	data, err := cache.LoadPromptFile(absPath)
 	if err != nil {
 		return "", err
 	}
@@ -128,3 +116,79 @@ func ReadAgentPromptFile(path string, allowOutsideClaudeDir bool) (string, error
 func WrapTaskWithAgentPrompt(prompt string, task string) string {
 	return "<agent-prompt>\n" + prompt + "\n</agent-prompt>\n\n" + task
 }
+
+// isWithinDir reports whether path is dir itself or a descendant of it.
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.Clean(rel)
+	if rel == "." {
+		return true
+	}
+	if rel == ".." {
+		return false
+	}
+	prefix := ".." + string(os.PathSeparator)
+	return !strings.HasPrefix(rel, prefix)
+}
+
+// resolveUserAllowedDirs expands, resolves, and validates the user-supplied
+// config.PromptAllowedDirs() entries before they're merged into
+// ReadAgentPromptFile's allowedDirs. Each entry goes through the same `~`
+// expansion, filepath.Abs/Clean, and EvalSymlinks containment check as the
+// two built-in defaults, and - unless allowOutsideClaudeDir is true - is
+// dropped with a logWarn if it resolves outside the user's home directory,
+// so a stray config value can't be used to smuggle the allowlist open to
+// the whole filesystem.
+func resolveUserAllowedDirs(home string, allowOutsideClaudeDir bool) []string {
+	configured := config.PromptAllowedDirs()
+	if len(configured) == 0 {
+		return nil
+	}
+
+	resolvedHome, err := filepath.EvalSymlinks(home)
+	if err != nil {
+		resolvedHome = home
+	}
+	resolvedHome = filepath.Clean(resolvedHome)
+
+	dirs := make([]string, 0, len(configured))
+	for _, raw := range configured {
+		expanded := raw
+		if raw == "~" || strings.HasPrefix(raw, "~/") || strings.HasPrefix(raw, "~\\") {
+			if raw == "~" {
+				expanded = home
+			} else {
+				expanded = home + raw[1:]
+			}
+		}
+
+		absDir, err := filepath.Abs(expanded)
+		if err != nil {
+			logWarn(fmt.Sprintf("prompt.allowed_dirs: skipping %q: %v", raw, err))
+			continue
+		}
+		absDir = filepath.Clean(absDir)
+
+		if allowOutsideClaudeDir {
+			dirs = append(dirs, absDir)
+			continue
+		}
+
+		resolvedDir, err := filepath.EvalSymlinks(absDir)
+		if err != nil {
+			resolvedDir = absDir
+		}
+		resolvedDir = filepath.Clean(resolvedDir)
+
+		if !isWithinDir(resolvedDir, resolvedHome) {
+			logWarn(fmt.Sprintf("prompt.allowed_dirs: ignoring %q, it resolves outside the home directory", raw))
+			continue
+		}
+
+		dirs = append(dirs, absDir)
+	}
+	return dirs
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "codeagent-wrapper/internal/config"
+)
+
+func TestReadAgentPromptFile_UserAllowedDirSymlinkEscapeRejected(t *testing.T) {
+	home := t.TempDir()
+	outside := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(outside, "agent.md"), []byte("outside prompt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(home, "escape-link")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unsupported in this environment: %v", err)
+	}
+
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("CODEAGENT_PROMPT_ALLOWED_DIRS", link)
+	config.ResetPromptAllowedDirsForTest()
+	t.Cleanup(config.ResetPromptAllowedDirsForTest)
+
+	_, err := ReadAgentPromptFile(filepath.Join(link, "agent.md"), false)
+	if err == nil {
+		t.Fatal("expected an error reading a prompt file via a user-allowed dir that symlinks outside the home directory")
+	}
+}
+
+func TestReadAgentPromptFile_UserAllowedDirWithinHomeAccepted(t *testing.T) {
+	home := t.TempDir()
+	extra := filepath.Join(home, "prompts")
+	if err := os.MkdirAll(extra, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(extra, "agent.md"), []byte("extra prompt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("CODEAGENT_PROMPT_ALLOWED_DIRS", extra)
+	config.ResetPromptAllowedDirsForTest()
+	t.Cleanup(config.ResetPromptAllowedDirsForTest)
+
+	got, err := ReadAgentPromptFile(filepath.Join(extra, "agent.md"), false)
+	if err != nil {
+		t.Fatalf("ReadAgentPromptFile() error = %v", err)
+	}
+	if got != "extra prompt" {
+		t.Fatalf("ReadAgentPromptFile() = %q, want %q", got, "extra prompt")
+	}
+}
@@ -0,0 +1,22 @@
+package executor
+
+import (
+	"fmt"
+
+	config "codeagent-wrapper/internal/config"
+)
+
+// WatchBackendConfigReloads subscribes to config.SubscribeReload so that,
+// once called, a rotated API key or changed default backend/model in
+// ~/.codeagent/models.json is logged as soon as the watcher picks it up,
+// instead of only being noticed on restart. No command of the wrapper calls
+// it, so parallel mode does not log reloads on its own. ResolveAgentConfig
+// and ResolveBackendConfig already read the live, watcher-swapped config
+// pointer on every call, so there's no executor-side cache to invalidate
+// here - each task's agent/backend resolution naturally picks up the new
+// config on its next call; this just surfaces that in the task log.
+func WatchBackendConfigReloads() {
+	config.SubscribeReload(func(cfg *config.ModelsConfig) {
+		logInfo(fmt.Sprintf("models config reloaded: default_backend=%s default_model=%s", cfg.DefaultBackend, cfg.DefaultModel))
+	})
+}
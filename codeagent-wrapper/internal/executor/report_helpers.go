@@ -1,13 +1,44 @@
 package executor
 
-import "strings"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// goTestEvent mirrors one line of `go test -json` output.
+type goTestEvent struct {
+	Time    string  `json:"Time"`
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Output  string  `json:"Output"`
+	Elapsed float64 `json:"Elapsed"`
+}
+
+func (e goTestEvent) key() string { return e.Package + "\x00" + e.Test }
+
+var (
+	coverFuncLineRe = regexp.MustCompile(`^(\S+\.go:\d+:\s*\S+)\s+(\d+(?:\.\d+)?)%`)
+	coverStmtLineRe = regexp.MustCompile(`coverage:\s*(\d+(?:\.\d+)?)%\s+of statements`)
+)
 
-// extractCoverageGap extracts what's missing from coverage reports.
+// extractCoverageGap extracts what's missing from coverage reports. It
+// prefers percentage-bearing lines (go tool cover -func output, or the
+// standard "coverage: XX.X% of statements" line, including inside
+// `go test -json` Output events) and returns the lowest-covered one rather
+// than the first match, since that's the line worth acting on.
 func extractCoverageGap(message string) string {
 	if message == "" {
 		return ""
 	}
 
+	if gap, ok := lowestCoverageLine(message); ok {
+		return truncateLine(gap, 100)
+	}
+
 	lower := strings.ToLower(message)
 	lines := strings.Split(message, "\n")
 
@@ -19,28 +50,18 @@ func extractCoverageGap(message string) string {
 			strings.Contains(lineLower, "not covered") ||
 			strings.Contains(lineLower, "missing coverage") ||
 			strings.Contains(lineLower, "lines not covered") {
-			if len(line) > 100 {
-				return line[:97] + "..."
-			}
-			return line
+			return truncateLine(line, 100)
 		}
 
 		if strings.Contains(lineLower, "branch") && strings.Contains(lineLower, "not taken") {
-			if len(line) > 100 {
-				return line[:97] + "..."
-			}
-			return line
+			return truncateLine(line, 100)
 		}
 	}
 
 	if strings.Contains(lower, "function") && strings.Contains(lower, "0%") {
 		for _, line := range lines {
 			if strings.Contains(strings.ToLower(line), "0%") && strings.Contains(line, "function") {
-				line = strings.TrimSpace(line)
-				if len(line) > 100 {
-					return line[:97] + "..."
-				}
-				return line
+				return truncateLine(strings.TrimSpace(line), 100)
 			}
 		}
 	}
@@ -48,16 +69,134 @@ func extractCoverageGap(message string) string {
 	return ""
 }
 
+// lowestCoverageLine scans message's lines - and, for go test -json lines,
+// their Output fields - for coverFuncLineRe/coverStmtLineRe matches and
+// returns the one with the lowest percentage.
+func lowestCoverageLine(message string) (string, bool) {
+	best := ""
+	bestPct := 101.0
+	found := false
+
+	consider := func(line string) {
+		line = strings.TrimRight(line, "\r")
+		if m := coverFuncLineRe.FindStringSubmatch(line); m != nil {
+			if pct, err := strconv.ParseFloat(m[2], 64); err == nil && pct < bestPct {
+				bestPct, best, found = pct, strings.TrimSpace(line), true
+			}
+			return
+		}
+		if m := coverStmtLineRe.FindStringSubmatch(line); m != nil {
+			if pct, err := strconv.ParseFloat(m[1], 64); err == nil && pct < bestPct {
+				bestPct, best, found = pct, strings.TrimSpace(line), true
+			}
+		}
+	}
+
+	for _, line := range strings.Split(message, "\n") {
+		if event, ok := decodeGoTestEvent(line); ok {
+			consider(event.Output)
+			continue
+		}
+		consider(line)
+	}
+
+	return best, found
+}
+
+// decodeGoTestEvent reports whether line is a `go test -json` event line.
+func decodeGoTestEvent(line string) (goTestEvent, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return goTestEvent{}, false
+	}
+	var event goTestEvent
+	if err := json.Unmarshal([]byte(trimmed), &event); err != nil {
+		return goTestEvent{}, false
+	}
+	if event.Action == "" {
+		return goTestEvent{}, false
+	}
+	return event, true
+}
+
 // extractErrorDetail extracts meaningful error context from task output.
+//
+// It first checks whether message looks like `go test -json` output: lines
+// that decode to {Time, Action, Package, Test, Output}. When it is,
+// per-(Package, Test) Output is buffered until that test's matching
+// Action:"fail"/"pass"/"skip" arrives - "fail" keeps the buffered output,
+// anything else discards it - and the concatenated output of only the
+// failed tests is returned. Any line that isn't a test-event line falls
+// back to the substring heuristic below, so mixed JSON/plain output is
+// still handled per-line.
 func extractErrorDetail(message string, maxLen int) string {
 	if message == "" || maxLen <= 0 {
 		return ""
 	}
 
 	lines := strings.Split(message, "\n")
-	var errorLines []string
+
+	jsonFailures, plainLines, sawJSON := groupGoTestFailures(lines)
+	if sawJSON {
+		if len(jsonFailures) > 0 {
+			parts := jsonFailures
+			if plain := extractErrorDetailHeuristic(plainLines, maxLen); plain != "" {
+				parts = append(parts, plain)
+			}
+			return safeTruncate(strings.Join(parts, " | "), maxLen)
+		}
+		// JSON lines were present but nothing failed outright (e.g. a build
+		// error masquerading as no test events) - fall through to the
+		// heuristic over whatever plain lines remain.
+	}
+
+	return extractErrorDetailHeuristic(plainLines, maxLen)
+}
+
+// groupGoTestFailures walks lines looking for go test -json events. It
+// returns the concatenated Output of every (Package, Test) pair that ended
+// in Action:"fail", in the order each test failed, plus the non-JSON lines
+// interleaved in message (for heuristic fallback) and whether any JSON
+// event line was seen at all.
+func groupGoTestFailures(lines []string) (failures []string, plainLines []string, sawJSON bool) {
+	buffers := make(map[string][]string)
 
 	for _, line := range lines {
+		event, ok := decodeGoTestEvent(line)
+		if !ok {
+			plainLines = append(plainLines, line)
+			continue
+		}
+		sawJSON = true
+
+		key := event.key()
+		switch event.Action {
+		case "output":
+			buffers[key] = append(buffers[key], strings.TrimRight(event.Output, "\n"))
+		case "fail":
+			failures = append(failures, strings.TrimSpace(strings.Join(buffers[key], "")))
+			delete(buffers, key)
+		case "pass", "skip":
+			delete(buffers, key)
+		}
+	}
+
+	result := make([]string, 0, len(failures))
+	for _, f := range failures {
+		if f != "" {
+			result = append(result, f)
+		}
+	}
+	return result, plainLines, sawJSON
+}
+
+// extractErrorDetailHeuristic is the original substring-matching pass,
+// applied either to the full message (plain-text input) or to the non-JSON
+// lines left over from a mixed go test -json stream.
+func extractErrorDetailHeuristic(plainLines []string, maxLen int) string {
+	var errorLines []string
+
+	for _, line := range plainLines {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -87,11 +226,11 @@ func extractErrorDetail(message string, maxLen int) string {
 	}
 
 	if len(errorLines) == 0 {
-		start := len(lines) - 5
+		start := len(plainLines) - 5
 		if start < 0 {
 			start = 0
 		}
-		for _, line := range lines[start:] {
+		for _, line := range plainLines[start:] {
 			line = strings.TrimSpace(line)
 			if line != "" {
 				errorLines = append(errorLines, line)
@@ -102,3 +241,10 @@ func extractErrorDetail(message string, maxLen int) string {
 	result := strings.Join(errorLines, " | ")
 	return safeTruncate(result, maxLen)
 }
+
+func truncateLine(line string, maxLen int) string {
+	if len(line) <= maxLen {
+		return line
+	}
+	return line[:maxLen-3] + "..."
+}
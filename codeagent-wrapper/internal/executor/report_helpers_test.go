@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractErrorDetailPlainHeuristic(t *testing.T) {
+	message := "running suite\nassert failed: expected 2 got 3\nok"
+	got := extractErrorDetail(message, 200)
+	if !strings.Contains(got, "assert failed") {
+		t.Fatalf("expected heuristic match, got %q", got)
+	}
+}
+
+func TestExtractErrorDetailGoTestJSON(t *testing.T) {
+	lines := []string{
+		`{"Action":"run","Package":"pkg","Test":"TestA"}`,
+		`{"Action":"output","Package":"pkg","Test":"TestA","Output":"--- FAIL: TestA\n"}`,
+		`{"Action":"output","Package":"pkg","Test":"TestA","Output":"    want 1, got 2\n"}`,
+		`{"Action":"fail","Package":"pkg","Test":"TestA"}`,
+		`{"Action":"run","Package":"pkg","Test":"TestB"}`,
+		`{"Action":"output","Package":"pkg","Test":"TestB","Output":"ok\n"}`,
+		`{"Action":"pass","Package":"pkg","Test":"TestB"}`,
+	}
+	message := strings.Join(lines, "\n")
+
+	got := extractErrorDetail(message, 500)
+	if !strings.Contains(got, "FAIL: TestA") || !strings.Contains(got, "want 1, got 2") {
+		t.Fatalf("expected failed test output, got %q", got)
+	}
+	if strings.Contains(got, "TestB") {
+		t.Fatalf("did not expect passed test output, got %q", got)
+	}
+}
+
+func TestExtractErrorDetailMixedJSONAndPlain(t *testing.T) {
+	lines := []string{
+		`{"Action":"output","Package":"pkg","Test":"TestA","Output":"boom\n"}`,
+		`{"Action":"fail","Package":"pkg","Test":"TestA"}`,
+		"warning: unrelated plain-text noise",
+	}
+	message := strings.Join(lines, "\n")
+
+	got := extractErrorDetail(message, 500)
+	if !strings.Contains(got, "boom") {
+		t.Fatalf("expected JSON-sourced failure output, got %q", got)
+	}
+}
+
+func TestExtractCoverageGapPicksLowestPercentage(t *testing.T) {
+	message := strings.Join([]string{
+		"pkg/a.go:10:   FuncHigh   92.0%",
+		"pkg/b.go:20:   FuncLow    41.5%",
+		"coverage: 88.0% of statements",
+	}, "\n")
+
+	got := extractCoverageGap(message)
+	if !strings.Contains(got, "FuncLow") || !strings.Contains(got, "41.5%") {
+		t.Fatalf("expected lowest-covered line, got %q", got)
+	}
+}
+
+func TestExtractCoverageGapFromGoTestJSONEvents(t *testing.T) {
+	lines := []string{
+		`{"Action":"output","Package":"pkg","Output":"ok  pkg  0.01s  coverage: 63.2% of statements\n"}`,
+	}
+	got := extractCoverageGap(strings.Join(lines, "\n"))
+	if !strings.Contains(got, "63.2%") {
+		t.Fatalf("expected coverage line extracted from JSON event, got %q", got)
+	}
+}
+
+func TestExtractCoverageGapFallsBackToSubstringHeuristic(t *testing.T) {
+	got := extractCoverageGap("3 lines not covered in foo.go")
+	if got == "" {
+		t.Fatalf("expected fallback heuristic match")
+	}
+}
@@ -0,0 +1,209 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	config "codeagent-wrapper/internal/config"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ExitCodeResourceLimitExceeded mirrors the conventional 128+SIGKILL exit
+// code used when a task is force-killed for breaching its ResourceBudget.
+const ExitCodeResourceLimitExceeded = 137
+
+// consecutiveBreachesToKill is how many consecutive over-budget samples are
+// tolerated before the process is killed, so a brief CPU/memory spike
+// doesn't take down a healthy agent.
+const consecutiveBreachesToKill = 3
+
+// resourceSampleIntervalMS is how often RunWithResourceBudget samples a
+// task's backend process, in milliseconds. It is a var (not a const), with
+// SetResourceSampleInterval as a test hook, so tests don't have to wait out
+// the real-world default to observe a breach.
+var resourceSampleIntervalMS atomic.Int64
+
+func init() {
+	resourceSampleIntervalMS.Store(int64(2 * time.Second / time.Millisecond))
+}
+
+func resourceSampleInterval() time.Duration {
+	return time.Duration(resourceSampleIntervalMS.Load()) * time.Millisecond
+}
+
+// ResourceSampler accumulates the peak RSS and total CPU time observed
+// across a task's lifetime, for the peak-usage summary RunWithResourceBudget
+// logs once the task completes.
+type ResourceSampler struct {
+	mu          sync.Mutex
+	peakMemMB   int64
+	totalCPUSec float64
+}
+
+// Peak returns the peak memory, in MB, and the total CPU-seconds consumed
+// across every sample taken so far.
+func (s *ResourceSampler) Peak() (peakMemMB int64, totalCPUSec float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peakMemMB, s.totalCPUSec
+}
+
+// sample walks pid and its Children() recursively, so shelled-out
+// subprocesses count toward the same budget as the agent's own process,
+// and returns the combined RSS (MB) and CPU% across the whole tree. It
+// also folds the sample into the sampler's running peak/total.
+func (s *ResourceSampler) sample(pid int32) (memMB int64, cpuPercent float64, ok bool) {
+	root, err := process.NewProcess(pid)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	procs := append([]*process.Process{root}, descendants(root)...)
+
+	var rss int64
+	var cpu float64
+	for _, p := range procs {
+		if info, err := p.MemoryInfo(); err == nil && info != nil {
+			rss += int64(info.RSS)
+		}
+		if pct, err := p.CPUPercent(); err == nil {
+			cpu += pct
+		}
+	}
+	memMB = rss / (1 << 20)
+
+	s.mu.Lock()
+	if memMB > s.peakMemMB {
+		s.peakMemMB = memMB
+	}
+	s.totalCPUSec += cpu / 100 * resourceSampleInterval().Seconds()
+	s.mu.Unlock()
+
+	return memMB, cpu, true
+}
+
+// descendants recursively collects every Children() of proc, so a backend
+// that shells out (e.g. codex running a build command) is charged against
+// the same ResourceBudget as the parent agent process.
+func descendants(proc *process.Process) []*process.Process {
+	children, err := proc.Children()
+	if err != nil || len(children) == 0 {
+		return nil
+	}
+	all := append([]*process.Process{}, children...)
+	for _, child := range children {
+		all = append(all, descendants(child)...)
+	}
+	return all
+}
+
+// pidToResourceInt32 mirrors internal/logger's pidToInt32: gopsutil's
+// process.Process is keyed by int32, so an out-of-range pid is reported as
+// "can't sample" rather than silently wrapping.
+func pidToResourceInt32(pid int) (int32, bool) {
+	if pid <= 0 || pid > math.MaxInt32 {
+		return 0, false
+	}
+	return int32(pid), true
+}
+
+func overBudget(budget config.ResourceBudget, memMB int64, cpuPercent float64) bool {
+	if budget.MaxMemoryMB > 0 && memMB > budget.MaxMemoryMB {
+		return true
+	}
+	if budget.MaxCPUPercent > 0 && cpuPercent > budget.MaxCPUPercent {
+		return true
+	}
+	return false
+}
+
+// RunWithResourceBudget runs fn with a ticking ResourceSampler watching
+// pid (and proc for signaling) against task.ResourceBudget. It is a no-op
+// wrapper - fn runs directly on ctx - when task.ResourceBudget.IsZero().
+//
+// Once a sample exceeds MaxMemoryMB or MaxCPUPercent for
+// consecutiveBreachesToKill ticks in a row, or the task outlives
+// MaxWallSeconds, the sampler SIGTERMs proc, waits the configured
+// force-kill grace period (SetForceKillDelay), then cancels ctx to force-
+// kill the subprocess - the same cancel-forces-kill path RunWithDeadline
+// uses for an expired deadline. The result's ExitCode is normalized to
+// ExitCodeResourceLimitExceeded in that case. Either way, the peak memory
+// and total CPU seconds observed are logged via logInfo before returning.
+func RunWithResourceBudget(ctx context.Context, task TaskSpec, pid int, proc processHandle, fn func(context.Context) TaskResult) TaskResult {
+	if task.ResourceBudget.IsZero() {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sampler := &ResourceSampler{}
+	var exceeded atomic.Bool
+	start := time.Now()
+	ticker := time.NewTicker(resourceSampleInterval())
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		consecutiveBreaches := 0
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				breached := time.Since(start) > wallBudget(task.ResourceBudget)
+				if pid32, ok := pidToResourceInt32(pid); ok {
+					if memMB, cpuPercent, ok := sampler.sample(pid32); ok {
+						breached = breached || overBudget(task.ResourceBudget, memMB, cpuPercent)
+					}
+				}
+
+				if !breached {
+					consecutiveBreaches = 0
+					continue
+				}
+				consecutiveBreaches++
+				if consecutiveBreaches < consecutiveBreachesToKill {
+					continue
+				}
+
+				exceeded.Store(true)
+				_ = sendTermSignal(proc)
+				grace := time.Duration(forceKillDelay.Load()) * time.Second
+				if grace <= 0 {
+					cancel()
+				} else {
+					time.AfterFunc(grace, cancel)
+				}
+				return
+			}
+		}
+	}()
+
+	result := fn(ctx)
+	close(done)
+
+	peakMemMB, totalCPUSec := sampler.Peak()
+	logInfo(fmt.Sprintf("task %s: peak memory %dMB, total CPU %.1fs", task.ID, peakMemMB, totalCPUSec))
+
+	if exceeded.Load() {
+		result.ExitCode = ExitCodeResourceLimitExceeded
+		result.Error = "resource limit exceeded"
+	}
+	return result
+}
+
+// wallBudget returns task's MaxWallSeconds as a Duration, or an effectively
+// unbounded duration when unset so the ">" comparison in the sampling loop
+// never trips.
+func wallBudget(budget config.ResourceBudget) time.Duration {
+	if budget.MaxWallSeconds <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(budget.MaxWallSeconds) * time.Second
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	config "codeagent-wrapper/internal/config"
+)
+
+func TestRunWithResourceBudgetNoopWhenZero(t *testing.T) {
+	task := TaskSpec{ID: "budget-zero"}
+
+	result := RunWithResourceBudget(context.Background(), task, os.Getpid(), nil, func(ctx context.Context) TaskResult {
+		return TaskResult{TaskID: task.ID, ExitCode: 0}
+	})
+
+	if result.ExitCode != 0 || result.Error != "" {
+		t.Fatalf("expected untouched result, got %+v", result)
+	}
+}
+
+func TestRunWithResourceBudgetWallSecondsExceeded(t *testing.T) {
+	defer SetResourceSampleInterval(5 * time.Millisecond)()
+	defer SetForceKillDelay(0)()
+
+	task := TaskSpec{ID: "budget-wall", ResourceBudget: config.ResourceBudget{MaxWallSeconds: 1}}
+
+	result := RunWithResourceBudget(context.Background(), task, os.Getpid(), nil, func(ctx context.Context) TaskResult {
+		<-ctx.Done()
+		return TaskResult{TaskID: task.ID, ExitCode: -1}
+	})
+
+	if result.ExitCode != ExitCodeResourceLimitExceeded {
+		t.Fatalf("ExitCode = %d, want %d", result.ExitCode, ExitCodeResourceLimitExceeded)
+	}
+	if result.Error != "resource limit exceeded" {
+		t.Fatalf("Error = %q, want %q", result.Error, "resource limit exceeded")
+	}
+}
+
+func TestRunWithResourceBudgetNormalCompletionUnaffected(t *testing.T) {
+	defer SetResourceSampleInterval(5 * time.Millisecond)()
+
+	task := TaskSpec{ID: "budget-normal", ResourceBudget: config.ResourceBudget{MaxMemoryMB: 1 << 30}}
+
+	result := RunWithResourceBudget(context.Background(), task, os.Getpid(), nil, func(ctx context.Context) TaskResult {
+		return TaskResult{TaskID: task.ID, ExitCode: 0}
+	})
+
+	if result.ExitCode != 0 || result.Error != "" {
+		t.Fatalf("expected untouched result, got %+v", result)
+	}
+}
@@ -0,0 +1,17 @@
+package executor
+
+import (
+	resources "codeagent-wrapper/internal/resources"
+)
+
+// ApplyResourceLimits places pid under a cgroup scope enforcing spec's
+// Resources, once the caller has started the task's backend process. It is
+// a no-op when spec.Resources is the zero value. The returned cleanup func
+// should be run after the process has exited (see the ProcessHandle
+// kill/wait path); cgroupfs refuses to remove a non-empty scope.
+func ApplyResourceLimits(spec TaskSpec, pid int) (cleanup func(), err error) {
+	if spec.Resources.IsZero() {
+		return func() {}, nil
+	}
+	return resources.Apply(spec.ID, pid, spec.Resources)
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	backend "codeagent-wrapper/internal/backend"
+)
+
+// RetryPolicy configures exponential-backoff-with-full-jitter retries for a
+// single TaskSpec. A nil policy, or MaxAttempts <= 1, means no retries: the
+// task runs exactly once.
+type RetryPolicy struct {
+	MaxAttempts  int           `json:"max_attempts,omitempty"`
+	InitialDelay time.Duration `json:"initial_delay,omitempty"`
+	MaxDelay     time.Duration `json:"max_delay,omitempty"`
+	Multiplier   float64       `json:"multiplier,omitempty"`
+
+	// RetryOn is a list of glob patterns (see backend.MatchGlob) matched
+	// against a failed attempt's Error/Message text. If empty, any failure
+	// that backend.ClassifyFailure doesn't mark FailureNonRetryable is
+	// retried.
+	RetryOn []string `json:"retry_on,omitempty"`
+}
+
+const (
+	defaultRetryInitialDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay     = 30 * time.Second
+	defaultRetryMultiplier   = 2.0
+)
+
+// backoffDelay computes the full-jitter exponential backoff delay for the
+// given zero-based attempt number: rand.Int63n(min(maxDelay, initialDelay *
+// multiplier^attempt)).
+func backoffDelay(policy RetryPolicy, attempt int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+	initial := policy.InitialDelay
+	if initial <= 0 {
+		initial = defaultRetryInitialDelay
+	}
+	maxDelay := policy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	capped := float64(initial) * math.Pow(multiplier, float64(attempt))
+	if capped > float64(maxDelay) {
+		capped = float64(maxDelay)
+	}
+	if capped <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(capped) + 1))
+}
+
+// shouldRetry reports whether result, the outcome of an attempt against
+// backendName, is worth retrying under policy.
+func shouldRetry(backendName string, result TaskResult, policy RetryPolicy) bool {
+	if result.ExitCode == 0 {
+		return false
+	}
+
+	message := result.Error
+	if message == "" {
+		message = result.Message
+	}
+
+	if len(policy.RetryOn) > 0 {
+		return backend.MatchesAnyGlob(message, policy.RetryOn)
+	}
+
+	return backend.ClassifyFailure(backendName, message) != backend.FailureNonRetryable
+}
+
+// RunWithRetry runs task via runTaskFn, retrying on transient failures per
+// task.RetryPolicy with exponential backoff and full jitter. Each retried
+// attempt is preserved, LogPath included, under the final result's
+// PreviousAttempts so a failed run can still be debugged. ctx cancellation
+// aborts a pending backoff sleep and returns the last attempt's result.
+func RunWithRetry(ctx context.Context, task TaskSpec, backendName string, runTaskFn func(TaskSpec, int) TaskResult, timeout int) TaskResult {
+	policy := RetryPolicy{}
+	if task.RetryPolicy != nil {
+		policy = *task.RetryPolicy
+	}
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var previous []TaskResult
+	var result TaskResult
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		result = runTaskFn(task, timeout)
+		if attempt == maxAttempts-1 || !shouldRetry(backendName, result, policy) {
+			break
+		}
+
+		previous = append(previous, result)
+
+		timer := time.NewTimer(backoffDelay(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.PreviousAttempts = previous
+			return result
+		case <-timer.C:
+		}
+	}
+
+	result.PreviousAttempts = previous
+	return result
+}
@@ -0,0 +1,153 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunWithRetryRetriesRetryableFailureThenSucceeds(t *testing.T) {
+	task := TaskSpec{
+		ID: "retry-succeeds",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:  3,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     5 * time.Millisecond,
+		},
+	}
+
+	attempts := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		attempts++
+		if attempts < 3 {
+			return TaskResult{TaskID: task.ID, ExitCode: 1, Error: "429 rate limit", LogPath: "log-" + task.ID}
+		}
+		return TaskResult{TaskID: task.ID, ExitCode: 0}
+	}
+
+	result := RunWithRetry(context.Background(), task, "codex", runTaskFn, 0)
+
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+	}
+	if len(result.PreviousAttempts) != 2 {
+		t.Fatalf("PreviousAttempts = %d, want 2", len(result.PreviousAttempts))
+	}
+	if result.PreviousAttempts[0].LogPath != "log-retry-succeeds" {
+		t.Fatalf("PreviousAttempts[0].LogPath = %q, want preserved log path", result.PreviousAttempts[0].LogPath)
+	}
+}
+
+func TestRunWithRetryStopsOnNonRetryableFailure(t *testing.T) {
+	task := TaskSpec{
+		ID:          "retry-nonretryable",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond},
+	}
+
+	attempts := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		attempts++
+		return TaskResult{TaskID: task.ID, ExitCode: 1, Error: "prompt is too long"}
+	}
+
+	result := RunWithRetry(context.Background(), task, "codex", runTaskFn, 0)
+
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+	if result.ExitCode != 1 {
+		t.Fatalf("ExitCode = %d, want 1", result.ExitCode)
+	}
+}
+
+func TestRunWithRetryExhaustsMaxAttempts(t *testing.T) {
+	task := TaskSpec{
+		ID:          "retry-exhaust",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond},
+	}
+
+	attempts := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		attempts++
+		return TaskResult{TaskID: task.ID, ExitCode: 1, Error: "rate limit exceeded"}
+	}
+
+	result := RunWithRetry(context.Background(), task, "codex", runTaskFn, 0)
+
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if len(result.PreviousAttempts) != 1 {
+		t.Fatalf("PreviousAttempts = %d, want 1", len(result.PreviousAttempts))
+	}
+}
+
+func TestRunWithRetryHonorsRetryOnPatterns(t *testing.T) {
+	task := TaskSpec{
+		ID: "retry-patterns",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:  3,
+			InitialDelay: time.Millisecond,
+			RetryOn:      []string{"*flaky network*"},
+		},
+	}
+
+	attempts := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		attempts++
+		return TaskResult{TaskID: task.ID, ExitCode: 1, Error: "unrelated error"}
+	}
+
+	RunWithRetry(context.Background(), task, "codex", runTaskFn, 0)
+
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 (error doesn't match RetryOn)", attempts)
+	}
+}
+
+func TestRunWithRetryNoPolicyRunsOnce(t *testing.T) {
+	task := TaskSpec{ID: "retry-no-policy"}
+
+	attempts := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		attempts++
+		return TaskResult{TaskID: task.ID, ExitCode: 1, Error: "boom"}
+	}
+
+	RunWithRetry(context.Background(), task, "codex", runTaskFn, 0)
+
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRunWithRetryAbortsOnContextCancel(t *testing.T) {
+	task := TaskSpec{
+		ID:          "retry-cancel",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	runTaskFn := func(TaskSpec, int) TaskResult {
+		attempts++
+		cancel()
+		return TaskResult{TaskID: task.ID, ExitCode: 1, Error: "rate limit"}
+	}
+
+	start := time.Now()
+	result := RunWithRetry(ctx, task, "codex", runTaskFn, 0)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected cancellation to abort backoff sleep quickly, took %s", elapsed)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+	if result.ExitCode != 1 {
+		t.Fatalf("ExitCode = %d, want 1", result.ExitCode)
+	}
+}
@@ -0,0 +1,166 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// sinkSchemaVersion is embedded as "v" in every SinkEvent so downstream
+// consumers can detect breaking changes to the event schema.
+const sinkSchemaVersion = 1
+
+// Recognized SinkEvent.Type values, tracking a task's lifecycle through a
+// ResultSink.
+const (
+	SinkEventQueued    = "queued"
+	SinkEventRunning   = "running"
+	SinkEventProgress  = "progress"
+	SinkEventCompleted = "completed"
+	SinkEventFailed    = "failed"
+)
+
+// SinkEvent is one line of a ResultSink's NDJSON output.
+type SinkEvent struct {
+	Version   int         `json:"v"`
+	Type      string      `json:"type"`
+	TaskID    string      `json:"task_id"`
+	Timestamp time.Time   `json:"timestamp"`
+	KeyOutput string      `json:"key_output,omitempty"`
+	Result    *TaskResult `json:"result,omitempty"`
+}
+
+func newSinkEvent(eventType, taskID string) SinkEvent {
+	return SinkEvent{Version: sinkSchemaVersion, Type: eventType, TaskID: taskID, Timestamp: time.Now()}
+}
+
+// ResultSink receives a task's lifecycle events as it moves through
+// queued -> running -> progress* -> completed/failed.
+type ResultSink interface {
+	Emit(event SinkEvent) error
+}
+
+// EmitQueued, EmitRunning, EmitProgress, and EmitResult are convenience
+// constructors so callers don't need to build a SinkEvent by hand.
+func EmitQueued(sink ResultSink, taskID string) error {
+	return sink.Emit(newSinkEvent(SinkEventQueued, taskID))
+}
+
+func EmitRunning(sink ResultSink, taskID string) error {
+	return sink.Emit(newSinkEvent(SinkEventRunning, taskID))
+}
+
+func EmitProgress(sink ResultSink, taskID, keyOutput string) error {
+	event := newSinkEvent(SinkEventProgress, taskID)
+	event.KeyOutput = keyOutput
+	return sink.Emit(event)
+}
+
+func EmitResult(sink ResultSink, taskID string, result TaskResult) error {
+	eventType := SinkEventCompleted
+	if result.ExitCode != 0 {
+		eventType = SinkEventFailed
+	}
+	event := newSinkEvent(eventType, taskID)
+	event.Result = &result
+	return sink.Emit(event)
+}
+
+// NDJSONSink writes one JSON object per line to w. Writes are serialized so
+// concurrent tasks never interleave a partial line; per-task-id ordering
+// falls out naturally because a task's own events are always emitted
+// sequentially from that task's goroutine.
+type NDJSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	return &NDJSONSink{w: w}
+}
+
+func (s *NDJSONSink) Emit(event SinkEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(data)
+	return err
+}
+
+// MultiSink fans an event out to every configured sink, continuing on error
+// so one broken sink (e.g. an unreachable webhook) doesn't block the others.
+type MultiSink struct {
+	sinks []ResultSink
+}
+
+func NewMultiSink(sinks ...ResultSink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+func (s *MultiSink) Emit(event SinkEvent) error {
+	var errs []string
+	for _, sink := range s.sinks {
+		if err := sink.Emit(event); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("sink errors: %s", strings.Join(errs, "; "))
+}
+
+// WebhookSink POSTs each event as a JSON body to a configured URL.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Emit(event SinkEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(s.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook sink: %s returned status %d", s.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// NewProgressNotifier returns an onMessage-compatible closure (see
+// ParseJSONStreamInternal) that emits a "progress" SinkEvent carrying the
+// task's latest accumulated message each time it's called. messageFn should
+// return a snapshot of the task's current message (e.g. the buffer being
+// built up by the active parser.StreamParser).
+func NewProgressNotifier(sink ResultSink, taskID string, messageFn func() string) func() {
+	if sink == nil || messageFn == nil {
+		return func() {}
+	}
+	return func() {
+		_ = EmitProgress(sink, taskID, messageFn())
+	}
+}
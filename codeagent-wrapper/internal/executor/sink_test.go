@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+type fakeSink struct {
+	events []SinkEvent
+	err    error
+}
+
+func (s *fakeSink) Emit(event SinkEvent) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestNDJSONSinkWritesOneLinePerEventInOrder(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewNDJSONSink(&buf)
+
+	if err := EmitQueued(sink, "t1"); err != nil {
+		t.Fatalf("EmitQueued() error = %v", err)
+	}
+	if err := EmitRunning(sink, "t1"); err != nil {
+		t.Fatalf("EmitRunning() error = %v", err)
+	}
+	if err := EmitProgress(sink, "t1", "partial output"); err != nil {
+		t.Fatalf("EmitProgress() error = %v", err)
+	}
+	if err := EmitResult(sink, "t1", TaskResult{TaskID: "t1", ExitCode: 0}); err != nil {
+		t.Fatalf("EmitResult() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %v", len(lines), lines)
+	}
+
+	wantTypes := []string{SinkEventQueued, SinkEventRunning, SinkEventProgress, SinkEventCompleted}
+	for i, line := range lines {
+		var event SinkEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line %d not valid JSON: %v", i, err)
+		}
+		if event.Version != sinkSchemaVersion {
+			t.Errorf("line %d: v = %d, want %d", i, event.Version, sinkSchemaVersion)
+		}
+		if event.Type != wantTypes[i] {
+			t.Errorf("line %d: type = %q, want %q", i, event.Type, wantTypes[i])
+		}
+		if event.TaskID != "t1" {
+			t.Errorf("line %d: task_id = %q, want %q", i, event.TaskID, "t1")
+		}
+	}
+}
+
+func TestEmitResultPicksFailedForNonZeroExitCode(t *testing.T) {
+	sink := &fakeSink{}
+	if err := EmitResult(sink, "t1", TaskResult{TaskID: "t1", ExitCode: 1}); err != nil {
+		t.Fatalf("EmitResult() error = %v", err)
+	}
+	if got := sink.events[0].Type; got != SinkEventFailed {
+		t.Fatalf("Type = %q, want %q", got, SinkEventFailed)
+	}
+}
+
+func TestMultiSinkFansOutAndAggregatesErrors(t *testing.T) {
+	ok := &fakeSink{}
+	broken := &fakeSink{err: errors.New("unreachable")}
+	multi := NewMultiSink(ok, broken)
+
+	err := multi.Emit(newSinkEvent(SinkEventQueued, "t1"))
+	if err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Fatalf("expected aggregated error mentioning broken sink, got %v", err)
+	}
+	if len(ok.events) != 1 {
+		t.Fatalf("expected working sink to still receive the event, got %d events", len(ok.events))
+	}
+}
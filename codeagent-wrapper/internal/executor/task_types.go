@@ -1,11 +1,31 @@
 package executor
 
-import "context"
+import (
+	"context"
+	"time"
+
+	cache "codeagent-wrapper/internal/cache"
+	config "codeagent-wrapper/internal/config"
+	resources "codeagent-wrapper/internal/resources"
+)
 
 // ParallelConfig defines the JSON schema for parallel execution.
 type ParallelConfig struct {
 	Tasks         []TaskSpec `json:"tasks"`
 	GlobalBackend string     `json:"backend,omitempty"`
+
+	// MaxConcurrency bounds the number of tasks RunDAG runs at once. Zero
+	// falls back to the caller-supplied worker count (see
+	// config.ResolveMaxParallelWorkers).
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// FailFast makes RunDAG record every not-yet-started task as skipped,
+	// instead of running it, as soon as any task fails (non-zero exit code);
+	// in-flight tasks still run to completion.
+	FailFast bool `json:"fail_fast,omitempty"`
+	// ContinueOnError lets a task run even when one of its declared
+	// Dependencies failed. Without it, dependents of a failed task are
+	// recorded as skipped rather than executed.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
 }
 
 // TaskSpec describes an individual task entry in the parallel config.
@@ -20,12 +40,53 @@ type TaskSpec struct {
 	ReasoningEffort string          `json:"reasoning_effort,omitempty"`
 	Agent           string          `json:"agent,omitempty"`
 	PromptFile      string          `json:"prompt_file,omitempty"`
+	Profile         string          `json:"profile,omitempty"`
 	SkipPermissions bool            `json:"skip_permissions,omitempty"`
 	Mode            string          `json:"-"`
 	UseStdin        bool            `json:"-"`
 	Context         context.Context `json:"-"`
+
+	// Timeout, if positive, is converted to a Deadline of time.Now()+Timeout
+	// when the task starts. Deadline, if set directly, is honored as-is; the
+	// earlier of the two wins. See RunWithDeadline and SetDeadline.
+	Timeout  time.Duration `json:"timeout,omitempty"`
+	Deadline time.Time     `json:"deadline,omitempty"`
+
+	// Inputs holds the TaskResults of this task's Dependencies, populated by
+	// RunDAG immediately before invocation so the task's prompt can reference
+	// predecessor output (see templateTaskInputs).
+	Inputs map[string]TaskResult `json:"-"`
+
+	// RetryPolicy, if set, lets RunWithRetry retry a failing attempt with
+	// exponential backoff instead of returning the first failure.
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// CachePolicy controls RunWithCache's content-addressed result cache
+	// lookup/write for this task: "" or "off" disables it, "read-only" only
+	// serves hits, "read-write" serves hits and stores misses, and
+	// "refresh" always runs live but still stores the result. See
+	// cache.CachePolicy.
+	CachePolicy cache.CachePolicy `json:"cache_policy,omitempty"`
+
+	// Resources, if non-zero, caps the CPU/memory/IO/pids the task's backend
+	// process may consume via resources.Apply. On non-Linux platforms these
+	// caps are logged but not enforced; see internal/resources.
+	Resources resources.Limits `json:"resources,omitempty"`
+
+	// ResourceBudget, if non-zero, caps this task's backend process by live
+	// sampling rather than kernel enforcement - see RunWithResourceBudget.
+	// finalizeTask defaults it from the resolved agent's config.ResourceBudget
+	// (config.ResolveAgentConfig) when the task doesn't set one explicitly.
+	ResourceBudget config.ResourceBudget `json:"resource_budget,omitempty"`
 }
 
+// ExitCodeBackendUnavailable is reserved for runTaskFn implementations to
+// report that the backend CLI itself could not run (missing binary, auth
+// failure, rate limit) as opposed to the task failing on a working backend.
+// Callers that support backend fallback retry with the next configured
+// backend when they see this exit code.
+const ExitCodeBackendUnavailable = 17
+
 // TaskResult captures the execution outcome of a task.
 type TaskResult struct {
 	TaskID    string `json:"task_id"`
@@ -43,4 +104,10 @@ type TaskResult struct {
 	TestsPassed    int      `json:"tests_passed,omitempty"`    // number of tests passed
 	TestsFailed    int      `json:"tests_failed,omitempty"`    // number of tests failed
 	sharedLog      bool
+
+	// PreviousAttempts holds the results of earlier, retried attempts (most
+	// recent last) when RunWithRetry retried this task. Each entry keeps its
+	// own LogPath so a failed run can still be debugged after a later
+	// attempt succeeds or exhausts RetryPolicy.MaxAttempts.
+	PreviousAttempts []TaskResult `json:"previous_attempts,omitempty"`
 }
@@ -3,8 +3,10 @@ package executor
 import (
 	"context"
 	"os/exec"
+	"time"
 
 	backend "codeagent-wrapper/internal/backend"
+	cache "codeagent-wrapper/internal/cache"
 )
 
 type CommandRunner = commandRunner
@@ -16,6 +18,15 @@ func SetForceKillDelay(seconds int32) (restore func()) {
 	return func() { forceKillDelay.Store(prev) }
 }
 
+// SetResourceSampleInterval overrides how often RunWithResourceBudget
+// samples a task's backend process, so tests don't have to wait out the
+// real-world default to observe a breach.
+func SetResourceSampleInterval(d time.Duration) (restore func()) {
+	prev := resourceSampleIntervalMS.Load()
+	resourceSampleIntervalMS.Store(int64(d / time.Millisecond))
+	return func() { resourceSampleIntervalMS.Store(prev) }
+}
+
 func SetSelectBackendFn(fn func(string) (Backend, error)) (restore func()) {
 	prev := selectBackendFn
 	if fn != nil {
@@ -48,6 +59,23 @@ func SetNewCommandRunner(fn func(context.Context, string, ...string) CommandRunn
 	return func() { newCommandRunner = prev }
 }
 
+// SetSharedResultCache overrides the *cache.ResultCache RunWithCache uses,
+// so a test can point it at a t.TempDir() instead of sharedResultCache's
+// real home-directory lookup. The restore func only puts back the previous
+// *cache.ResultCache value; resultCacheOnce is consumed the first time this
+// is called and, deliberately, never reset (resetting it would mean copying
+// a sync.Once, which embeds a Mutex - copying a value of that shape, even
+// unlocked, is exactly what go vet's copylocks check flags), so once a test
+// process has called this, sharedResultCache's lazy home-directory lookup
+// never runs again in that process; every subsequent override goes through
+// this function instead.
+func SetSharedResultCache(rc *cache.ResultCache) (restore func()) {
+	resultCacheOnce.Do(func() {})
+	prev := resultCache
+	resultCache = rc
+	return func() { resultCache = prev }
+}
+
 func WithTaskLogger(ctx context.Context, logger *Logger) context.Context {
 	return withTaskLogger(ctx, logger)
 }
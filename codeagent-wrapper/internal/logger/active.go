@@ -24,24 +24,28 @@ func logDebug(msg string) {
 	if logger := activeLogger(); logger != nil {
 		logger.Debug(msg)
 	}
+	fireHooks(LevelDebug, msg)
 }
 
 func logInfo(msg string) {
 	if logger := activeLogger(); logger != nil {
 		logger.Info(msg)
 	}
+	fireHooks(LevelInfo, msg)
 }
 
 func logWarn(msg string) {
 	if logger := activeLogger(); logger != nil {
 		logger.Warn(msg)
 	}
+	fireHooks(LevelWarn, msg)
 }
 
 func logError(msg string) {
 	if logger := activeLogger(); logger != nil {
 		logger.Error(msg)
 	}
+	fireHooks(LevelError, msg)
 }
 
 func SetLogger(l *Logger) { setLogger(l) }
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrCleanupInProgress is returned by cleanupOldLogs when another process
+// (or goroutine, in-process) already holds the cleanup lock. The caller
+// gets a zero-value CleanupStats alongside it rather than a partial scan.
+var ErrCleanupInProgress = errors.New("logger: cleanup already in progress")
+
+const (
+	cleanupLockName           = "codeagent-wrapper-cleanup.lock"
+	defaultCleanupLockTimeout = 2 * time.Second
+	cleanupLockRetry          = 10 * time.Millisecond
+)
+
+// cleanupLockTimeout is a var (not a const) so tests can shrink it via
+// SetCleanupLockTimeout to exercise the "lock held by someone else" path
+// without waiting the full default timeout.
+var cleanupLockTimeout = defaultCleanupLockTimeout
+
+func cleanupLockPath(tempDir string) string {
+	return filepath.Join(tempDir, cleanupLockName)
+}
+
+// tryLockTempDir acquires an OS-level advisory lock on tempDir's cleanup
+// lock file (flock on unix, LockFileEx on windows), retrying briefly before
+// giving up so a cleanupOldLogs call racing a short-lived sibling call
+// still succeeds. ok is false if the lock is held by someone else for
+// longer than cleanupLockTimeout; unlock must be called exactly once, and
+// only when ok is true.
+func tryLockTempDir(tempDir string) (unlock func(), ok bool) {
+	f, err := os.OpenFile(cleanupLockPath(tempDir), os.O_CREATE|os.O_RDWR, 0o644) // #nosec G304 -- fixed lock file name under the system temp dir
+	if err != nil {
+		return nil, false
+	}
+
+	deadline := time.Now().Add(cleanupLockTimeout)
+	for {
+		if lockFile(f) {
+			return func() {
+				_ = unlockFile(f)
+				_ = f.Close()
+			}, true
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, false
+		}
+		time.Sleep(cleanupLockRetry)
+	}
+}
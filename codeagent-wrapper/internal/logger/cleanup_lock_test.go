@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCleanupOldLogsConcurrentCallsOnlyOneProceeds(t *testing.T) {
+	tempDir := setTempDirEnv(t, t.TempDir())
+	createTempLog(t, tempDir, "codeagent-wrapper-7100.log")
+
+	t.Cleanup(SetCleanupLockTimeout(50 * time.Millisecond))
+
+	stubProcessRunning(t, func(int) bool { return false })
+	stubProcessStartTime(t, func(int) time.Time { return time.Time{} })
+
+	// Hold the lock-winning goroutine inside its critical section longer
+	// than the loser's lock timeout, so the race has a deterministic
+	// outcome: one goroutine completes a real scan, the other gives up.
+	stubGlobLogFiles(t, func(pattern string) ([]string, error) {
+		time.Sleep(200 * time.Millisecond)
+		return filepath.Glob(pattern)
+	})
+
+	var wg sync.WaitGroup
+	stats := make([]CleanupStats, 2)
+	errs := make([]error, 2)
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			defer wg.Done()
+			stats[i], errs[i] = cleanupOldLogs()
+		}(i)
+		time.Sleep(10 * time.Millisecond) // give the first call a head start on the lock
+	}
+	wg.Wait()
+
+	var worked, inProgress int
+	for i := 0; i < 2; i++ {
+		switch {
+		case errors.Is(errs[i], ErrCleanupInProgress):
+			inProgress++
+			if !compareCleanupStats(stats[i], CleanupStats{}) {
+				t.Fatalf("expected zero-value stats alongside ErrCleanupInProgress, got %+v", stats[i])
+			}
+		case errs[i] == nil:
+			worked++
+		default:
+			t.Fatalf("unexpected error from cleanupOldLogs: %v", errs[i])
+		}
+	}
+	if worked != 1 || inProgress != 1 {
+		t.Fatalf("expected exactly one goroutine to do the work and the other to see ErrCleanupInProgress, got worked=%d inProgress=%d", worked, inProgress)
+	}
+}
+
+func TestCleanupOldLogsReleasesLockForNextSequentialCall(t *testing.T) {
+	tempDir := setTempDirEnv(t, t.TempDir())
+	createTempLog(t, tempDir, "codeagent-wrapper-7200.log")
+
+	stubProcessRunning(t, func(int) bool { return false })
+	stubProcessStartTime(t, func(int) time.Time { return time.Time{} })
+
+	if _, err := cleanupOldLogs(); err != nil {
+		t.Fatalf("first cleanupOldLogs() error = %v", err)
+	}
+	if _, err := cleanupOldLogs(); err != nil {
+		t.Fatalf("second cleanupOldLogs() error = %v, want lock released from first call", err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build unix || darwin || linux
+// +build unix darwin linux
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile attempts a non-blocking exclusive flock, returning true iff it
+// was acquired.
+func lockFile(f *os.File) bool {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) == nil
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
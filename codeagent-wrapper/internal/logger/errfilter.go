@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"path"
+	"strings"
+)
+
+// matchesAnyFilter reports whether entry matches patterns, a comma-separated
+// list of glob patterns compared against every slash-delimited prefix of
+// entry (so "foo/*" matches "foo/bar/baz"), mirroring the Go toolchain's
+// module.MatchPrefixPatterns approach to GONOSUMDB-style prefix globs. An
+// empty patterns string matches everything. Each individual pattern is
+// trimmed before matching; a malformed pattern (path.Match returning
+// ErrBadPattern) is silently skipped rather than treated as a match or an
+// error.
+func matchesAnyFilter(entry, patterns string) bool {
+	if patterns == "" {
+		return true
+	}
+
+	for _, pat := range strings.Split(patterns, ",") {
+		pat = strings.TrimSpace(pat)
+		if pat == "" {
+			continue
+		}
+		for _, prefix := range pathPrefixes(entry) {
+			if ok, err := path.Match(pat, prefix); err == nil && ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathPrefixes returns every slash-delimited prefix of p, shortest first:
+// pathPrefixes("foo/bar/baz") == []string{"foo", "foo/bar", "foo/bar/baz"}.
+func pathPrefixes(p string) []string {
+	parts := strings.Split(p, "/")
+	prefixes := make([]string, len(parts))
+	for i := range parts {
+		prefixes[i] = strings.Join(parts[:i+1], "/")
+	}
+	return prefixes
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// readLogFileFn reads a whole log file for scanErrorEntries; overridable in
+// tests to count (or fake) reads without touching the real file system.
+var readLogFileFn = os.ReadFile
+
+// tailHashBytes bounds how much of the file's tail is hashed to detect
+// appends cheaply, rather than hashing (or re-reading) the whole file on
+// every poll.
+const tailHashBytes = 64 * 1024
+
+// errScanCache records the file state ExtractRecentErrorsCached last scanned
+// under, so a repeated call against an unchanged file can skip the re-read.
+type errScanCache struct {
+	size    int64
+	modTime time.Time
+	tail    [sha256.Size]byte
+	entries []string
+}
+
+// ExtractRecentErrorsCached is like ExtractRecentErrors but scans l's log
+// file from disk instead of the in-memory ring buffer, caching the parsed
+// result keyed by (size, mtime, tail hash). A repeated call against an
+// unchanged file stats it and hashes its last tailHashBytes, but does not
+// re-read the whole file or re-parse it; any change to
+// size, mtime, or the hash of the last tailHashBytes triggers a full rescan.
+// This is for readers attached to a log file they didn't write themselves
+// (e.g. a TUI tailing another process's log), where the writer's in-memory
+// cache isn't available.
+func (l *Logger) ExtractRecentErrorsCached(maxEntries int) []string {
+	if l == nil || l.path == "" || maxEntries <= 0 {
+		return nil
+	}
+
+	info, err := fileStatFn(l.path)
+	if err != nil {
+		return nil
+	}
+	tail, err := tailHashOf(l.path, info.Size())
+	if err != nil {
+		return nil
+	}
+
+	l.scanMu.Lock()
+	defer l.scanMu.Unlock()
+
+	if l.errScan == nil || l.errScan.size != info.Size() || !l.errScan.modTime.Equal(info.ModTime()) || l.errScan.tail != tail {
+		entries, err := scanErrorEntries(l.path)
+		if err != nil {
+			return nil
+		}
+		l.errScan = &errScanCache{size: info.Size(), modTime: info.ModTime(), tail: tail, entries: entries}
+	}
+
+	entries := l.errScan.entries
+	n := maxEntries
+	if n > len(entries) {
+		n = len(entries)
+	}
+	out := make([]string, n)
+	copy(out, entries[len(entries)-n:])
+	return out
+}
+
+// tailHashOf hashes the last min(size, tailHashBytes) bytes of path.
+func tailHashOf(path string, size int64) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	f, err := os.Open(path) // #nosec G304 -- path is this Logger's own log file
+	if err != nil {
+		return sum, err
+	}
+	defer f.Close()
+
+	start := int64(0)
+	if size > tailHashBytes {
+		start = size - tailHashBytes
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return sum, err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// scanErrorEntries reads path and returns the message of every WARN/ERROR
+// line, in file order, recognizing both the FormatText "[ts] LEVEL msg" line
+// and a FormatJSON object per line.
+func scanErrorEntries(path string) ([]string, error) {
+	data, err := readLogFileFn(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var level, msg string
+		var ok bool
+		if strings.HasPrefix(line, "{") {
+			level, msg, ok = parseJSONErrorLine(line)
+		} else {
+			level, msg, ok = parseTextErrorLine(line)
+		}
+		if !ok {
+			continue
+		}
+		if level == "WARN" || level == "ERROR" {
+			entries = append(entries, msg)
+		}
+	}
+	return entries, nil
+}
+
+// parseTextErrorLine extracts the level and message from a FormatText line
+// of the shape "[ts] LEVEL msg".
+func parseTextErrorLine(line string) (level, msg string, ok bool) {
+	if !strings.HasPrefix(line, "[") {
+		return "", "", false
+	}
+	idx := strings.Index(line, "] ")
+	if idx < 0 {
+		return "", "", false
+	}
+	rest := line[idx+2:]
+	sp := strings.IndexByte(rest, ' ')
+	if sp < 0 {
+		return "", "", false
+	}
+	return rest[:sp], rest[sp+1:], true
+}
+
+// parseJSONErrorLine extracts the level and message from a FormatJSON line.
+func parseJSONErrorLine(line string) (level, msg string, ok bool) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		return "", "", false
+	}
+	level, _ = obj["level"].(string)
+	msg, _ = obj["msg"].(string)
+	if level == "" {
+		return "", "", false
+	}
+	return level, msg, true
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func stubReadLogFile(t *testing.T, fn func(string) ([]byte, error)) {
+	t.Helper()
+	t.Cleanup(SetReadLogFileFn(fn))
+}
+
+func TestExtractRecentErrorsCachedSkipsRereadWhenUnchanged(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Warn("disk full")
+	logger.Info("started")
+	logger.Flush()
+
+	var reads, stats int
+	stubReadLogFile(t, func(path string) ([]byte, error) {
+		reads++
+		return os.ReadFile(path)
+	})
+	stubFileStat(t, func(path string) (os.FileInfo, error) {
+		stats++
+		return os.Lstat(path)
+	})
+
+	// Simulate a TUI polling every 100ms against an unchanged file.
+	var got []string
+	for i := 0; i < 5; i++ {
+		got = logger.ExtractRecentErrorsCached(10)
+	}
+
+	if reads != 1 {
+		t.Fatalf("expected exactly one re-read across repeated polls of an unchanged file, got %d", reads)
+	}
+	if stats != 5 {
+		t.Fatalf("expected every poll to stat the file, got %d stats for 5 polls", stats)
+	}
+	if len(got) != 1 || got[0] != "disk full" {
+		t.Fatalf("ExtractRecentErrorsCached() = %v, want [\"disk full\"]", got)
+	}
+}
+
+func TestExtractRecentErrorsCachedRescansOnDrift(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Warn("first error")
+	logger.Flush()
+
+	var reads int
+	stubReadLogFile(t, func(path string) ([]byte, error) {
+		reads++
+		return os.ReadFile(path)
+	})
+
+	first := logger.ExtractRecentErrorsCached(10)
+	if len(first) != 1 || first[0] != "first error" {
+		t.Fatalf("first scan = %v, want [\"first error\"]", first)
+	}
+
+	logger.Error("second error")
+	logger.Flush()
+
+	second := logger.ExtractRecentErrorsCached(10)
+	if len(second) != 2 || second[0] != "first error" || second[1] != "second error" {
+		t.Fatalf("second scan = %v, want [\"first error\" \"second error\"]", second)
+	}
+	if reads != 2 {
+		t.Fatalf("expected a rescan after the file grew, got %d reads", reads)
+	}
+}
+
+func TestExtractRecentErrorsCachedParsesJSONFormat(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLoggerWithFormat(FormatJSON)
+	if err != nil {
+		t.Fatalf("NewLoggerWithFormat() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.ErrorFields("boom", F("code", 500))
+	logger.Flush()
+
+	got := logger.ExtractRecentErrorsCached(10)
+	if len(got) != 1 || got[0] != "boom" {
+		t.Fatalf("ExtractRecentErrorsCached() = %v, want [\"boom\"]", got)
+	}
+}
+
+func TestExtractRecentErrorsCachedAppliesMaxEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 5; i++ {
+		logger.Error(fmt.Sprintf("err %d", i))
+	}
+	logger.Flush()
+
+	got := logger.ExtractRecentErrorsCached(2)
+	if len(got) != 2 || got[0] != "err 3" || got[1] != "err 4" {
+		t.Fatalf("ExtractRecentErrorsCached(2) = %v, want last 2 entries", got)
+	}
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// HTTPHook batches entries and POSTs them as a JSON array to a collector
+// endpoint, flushing either when the batch reaches batchSize entries or
+// flushInterval elapses, whichever comes first.
+type HTTPHook struct {
+	url           string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+
+	mu      sync.Mutex
+	pending []Entry
+	timer   *time.Timer
+	levels  []Level
+}
+
+const (
+	defaultHTTPBatchSize     = 20
+	defaultHTTPFlushInterval = 5 * time.Second
+	defaultHTTPMaxRetries    = 3
+)
+
+// NewHTTPHook returns a Hook that batches entries and posts them to url. It
+// fires for the given levels (all levels if none given).
+func NewHTTPHook(url string, levels ...Level) *HTTPHook {
+	if len(levels) == 0 {
+		levels = []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+	}
+	return &HTTPHook{
+		url:           url,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		batchSize:     defaultHTTPBatchSize,
+		flushInterval: defaultHTTPFlushInterval,
+		maxRetries:    defaultHTTPMaxRetries,
+		levels:        levels,
+	}
+}
+
+func (h *HTTPHook) Levels() []Level { return h.levels }
+
+func (h *HTTPHook) Fire(entry Entry) error {
+	h.mu.Lock()
+	h.pending = append(h.pending, entry)
+	full := len(h.pending) >= h.batchSize
+	if h.timer == nil {
+		h.timer = time.AfterFunc(h.flushInterval, func() { _ = h.Flush() })
+	}
+	h.mu.Unlock()
+
+	if full {
+		return h.Flush()
+	}
+	return nil
+}
+
+// Flush sends any buffered entries immediately, retrying with exponential
+// backoff on failure. It returns the last error if all retries are
+// exhausted; the batch is dropped rather than retried forever so one
+// unreachable collector can't grow unbounded memory.
+func (h *HTTPHook) Flush() error {
+	h.mu.Lock()
+	batch := h.pending
+	h.pending = nil
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	h.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	delay := 200 * time.Millisecond
+	for attempt := 0; attempt < h.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if lastErr = h.post(body); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("logger: http hook failed after %d attempts: %w", h.maxRetries, lastErr)
+}
+
+func (h *HTTPHook) post(body []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package logger
+
+import "errors"
+
+// JournaldHook is unavailable on this platform; journald only runs on Linux.
+type JournaldHook struct{}
+
+// NewJournaldHook always fails outside Linux.
+func NewJournaldHook(levels ...Level) (*JournaldHook, error) {
+	return nil, errors.New("logger: journald hook is only supported on Linux")
+}
+
+func (h *JournaldHook) Levels() []Level        { return nil }
+func (h *JournaldHook) Fire(entry Entry) error { return nil }
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"os"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// JSONFileHook appends one NDJSON line per entry to a file, suitable for
+// ingestion by log shippers (Vector, Fluent Bit, etc.).
+type JSONFileHook struct {
+	mu     sync.Mutex
+	file   *os.File
+	levels []Level
+}
+
+type jsonFileRecord struct {
+	Time    string `json:"ts"`
+	Level   string `json:"level"`
+	Message string `json:"msg"`
+	TaskID  string `json:"task_id,omitempty"`
+	PID     int    `json:"pid"`
+}
+
+// NewJSONFileHook opens (creating/appending to) path and returns a Hook
+// firing for the given levels (all levels if none given).
+func NewJSONFileHook(path string, levels ...Level) (*JSONFileHook, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644) // #nosec G304 -- path is an operator-configured log destination, not untrusted input
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		levels = []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+	}
+	return &JSONFileHook{file: f, levels: levels}, nil
+}
+
+func (h *JSONFileHook) Levels() []Level { return h.levels }
+
+func (h *JSONFileHook) Fire(entry Entry) error {
+	record := jsonFileRecord{
+		Time:    entry.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		TaskID:  entry.TaskID,
+		PID:     entry.PID,
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.file.Write(line)
+	return err
+}
+
+// Close flushes and closes the underlying file.
+func (h *JSONFileHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Close()
+}
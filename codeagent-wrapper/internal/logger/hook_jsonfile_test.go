@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+func TestJSONFileHookAppendsNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks.ndjson")
+
+	hook, err := NewJSONFileHook(path, LevelWarn)
+	if err != nil {
+		t.Fatalf("NewJSONFileHook() error = %v", err)
+	}
+	defer hook.Close()
+
+	if err := hook.Fire(Entry{Time: time.Now(), Level: LevelWarn, Message: "disk low", PID: 123}); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	if err := hook.Fire(Entry{Time: time.Now(), Level: LevelWarn, Message: "disk lower", PID: 123}); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open written file: %v", err)
+	}
+	defer f.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+		var record jsonFileRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %d not valid JSON: %v", lines, err)
+		}
+		if record.Level != "warn" || record.PID != 123 {
+			t.Errorf("unexpected record: %+v", record)
+		}
+	}
+	if lines != 2 {
+		t.Fatalf("expected 2 NDJSON lines, got %d", lines)
+	}
+}
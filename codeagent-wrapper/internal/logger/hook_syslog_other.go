@@ -0,0 +1,17 @@
+//go:build !(unix || darwin || linux)
+// +build !unix,!darwin,!linux
+
+package logger
+
+import "errors"
+
+// SyslogHook is unavailable on this platform; log/syslog only supports Unix.
+type SyslogHook struct{}
+
+// NewSyslogHook always fails on non-Unix platforms.
+func NewSyslogHook(levels ...Level) (*SyslogHook, error) {
+	return nil, errors.New("logger: syslog hook is not supported on this platform")
+}
+
+func (h *SyslogHook) Levels() []Level        { return nil }
+func (h *SyslogHook) Fire(entry Entry) error { return nil }
@@ -0,0 +1,43 @@
+//go:build unix || darwin || linux
+// +build unix darwin linux
+
+package logger
+
+import "log/syslog"
+
+// SyslogHook forwards entries to the local syslog daemon via log/syslog.
+type SyslogHook struct {
+	writer *syslog.Writer
+	levels []Level
+}
+
+// NewSyslogHook dials the local syslog daemon tagged as WrapperName and
+// returns a Hook firing for the given levels (all levels if none given).
+func NewSyslogHook(levels ...Level) (*SyslogHook, error) {
+	w, err := syslog.New(syslog.LOG_INFO, WrapperName)
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		levels = []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+	}
+	return &SyslogHook{writer: w, levels: levels}, nil
+}
+
+func (h *SyslogHook) Levels() []Level { return h.levels }
+
+func (h *SyslogHook) Fire(entry Entry) error {
+	switch entry.Level {
+	case LevelError:
+		return h.writer.Err(entry.Message)
+	case LevelWarn:
+		return h.writer.Warning(entry.Message)
+	case LevelDebug:
+		return h.writer.Debug(entry.Message)
+	default:
+		return h.writer.Info(entry.Message)
+	}
+}
+
+// Close releases the underlying syslog connection.
+func (h *SyslogHook) Close() error { return h.writer.Close() }
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level identifies the severity of a log Entry delivered to a Hook.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the lowercase name used in structured output (e.g. jsonfile).
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Entry is the record handed to each registered Hook. TaskID is left empty
+// by the package-level logDebug/logInfo/logWarn/logError helpers, since the
+// active logger doesn't track per-task context; it exists so a caller with
+// task context of its own can populate it before calling Fire directly.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	TaskID  string
+	PID     int
+}
+
+// Hook receives log entries at the levels it declares interest in. Fire
+// should return quickly; slow sinks (e.g. http) should buffer internally.
+type Hook interface {
+	Levels() []Level
+	Fire(entry Entry) error
+}
+
+var (
+	hooksMu sync.RWMutex
+	hooks   []Hook
+)
+
+// AddHook registers a Hook to receive future log entries at its declared
+// Levels. Hooks are fired in registration order; a panic or error from one
+// hook does not prevent the others from running.
+func AddHook(h Hook) {
+	if h == nil {
+		return
+	}
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, h)
+}
+
+// RemoveHook unregisters a previously added Hook. It is a no-op if h was
+// never registered.
+func RemoveHook(h Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	for i, existing := range hooks {
+		if existing == h {
+			hooks = append(hooks[:i], hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// ClearHooks removes all registered hooks. Intended for tests.
+func ClearHooks() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = nil
+}
+
+func fireHooks(level Level, msg string) {
+	hooksMu.RLock()
+	active := hooks
+	hooksMu.RUnlock()
+	if len(active) == 0 {
+		return
+	}
+
+	entry := Entry{Time: time.Now(), Level: level, Message: msg, PID: os.Getpid()}
+	for _, h := range active {
+		fireHookSafely(h, entry)
+	}
+}
+
+// fireHookSafely isolates a single hook's failure (including a panic) so
+// one broken sink cannot break logging for the others.
+func fireHookSafely(h Hook, entry Entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "codeagent-wrapper: log hook panicked: %v\n", r)
+		}
+	}()
+
+	if !levelMatches(h, entry.Level) {
+		return
+	}
+	if err := h.Fire(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "codeagent-wrapper: log hook failed: %v\n", err)
+	}
+}
+
+func levelMatches(h Hook, level Level) bool {
+	for _, l := range h.Levels() {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
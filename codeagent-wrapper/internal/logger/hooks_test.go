@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeHook struct {
+	levels  []Level
+	entries []Entry
+	failErr error
+}
+
+func (h *fakeHook) Levels() []Level { return h.levels }
+
+func (h *fakeHook) Fire(entry Entry) error {
+	if h.failErr != nil {
+		return h.failErr
+	}
+	h.entries = append(h.entries, entry)
+	return nil
+}
+
+func TestFireHooksOnlyDeliversDeclaredLevels(t *testing.T) {
+	ClearHooks()
+	t.Cleanup(ClearHooks)
+
+	hook := &fakeHook{levels: []Level{LevelWarn, LevelError}}
+	AddHook(hook)
+
+	fireHooks(LevelInfo, "ignored")
+	fireHooks(LevelWarn, "warned")
+	fireHooks(LevelError, "errored")
+
+	if len(hook.entries) != 2 {
+		t.Fatalf("expected 2 delivered entries, got %d", len(hook.entries))
+	}
+	if hook.entries[0].Message != "warned" || hook.entries[1].Message != "errored" {
+		t.Fatalf("unexpected entries: %+v", hook.entries)
+	}
+}
+
+func TestFireHooksIsolatesFailures(t *testing.T) {
+	ClearHooks()
+	t.Cleanup(ClearHooks)
+
+	failing := &fakeHook{levels: []Level{LevelInfo}, failErr: errors.New("boom")}
+	healthy := &fakeHook{levels: []Level{LevelInfo}}
+	AddHook(failing)
+	AddHook(healthy)
+
+	fireHooks(LevelInfo, "hello")
+
+	if len(healthy.entries) != 1 {
+		t.Fatalf("expected the healthy hook to still receive the entry despite the failing hook, got %d entries", len(healthy.entries))
+	}
+}
+
+func TestRemoveHook(t *testing.T) {
+	ClearHooks()
+	t.Cleanup(ClearHooks)
+
+	hook := &fakeHook{levels: []Level{LevelInfo}}
+	AddHook(hook)
+	RemoveHook(hook)
+
+	fireHooks(LevelInfo, "should not be delivered")
+
+	if len(hook.entries) != 0 {
+		t.Fatalf("expected no entries after RemoveHook, got %d", len(hook.entries))
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	cases := map[Level]string{
+		LevelDebug: "debug",
+		LevelInfo:  "info",
+		LevelWarn:  "warn",
+		LevelError: "error",
+		Level(99):  "unknown",
+	}
+	for level, want := range cases {
+		if got := level.String(); got != want {
+			t.Errorf("Level(%d).String() = %q, want %q", level, got, want)
+		}
+	}
+}
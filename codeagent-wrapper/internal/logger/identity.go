@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// fingerprintSidecarSuffix names the small sidecar file a Logger writes
+// alongside a newly created log, holding the fingerprint of the process
+// that created it. A sidecar is used instead of a literal first line in the
+// log itself so existing line-oriented consumers of the log (scanners,
+// ExtractRecentErrors, tests asserting an exact line count) see exactly the
+// lines they wrote and nothing else.
+const fingerprintSidecarSuffix = ".fp"
+
+func fingerprintSidecarPath(logPath string) string { return logPath + fingerprintSidecarSuffix }
+
+// ProcessIdentity abstracts PID liveness/start-time/fingerprint lookups.
+// The default implementation is backed by gopsutil plus a platform-specific
+// Fingerprint (see identity_linux.go/identity_darwin.go/identity_windows.go);
+// tests and alternate deployments (e.g. a container-aware PID mapper) can
+// substitute their own via SetProcessIdentity.
+type ProcessIdentity interface {
+	IsAlive(pid int) (bool, error)
+	StartTime(pid int) (time.Time, error)
+	Fingerprint(pid int) (string, error)
+}
+
+// gopsutilIdentity is the default ProcessIdentity, built on the existing
+// isProcessRunning/getProcessStartTime helpers plus a platform Fingerprint.
+type gopsutilIdentity struct{}
+
+func (gopsutilIdentity) IsAlive(pid int) (bool, error) {
+	return isProcessRunning(pid), nil
+}
+
+func (gopsutilIdentity) StartTime(pid int) (time.Time, error) {
+	st := getProcessStartTime(pid)
+	if st.IsZero() {
+		return time.Time{}, fmt.Errorf("logger: could not determine start time for pid %d", pid)
+	}
+	return st, nil
+}
+
+func (gopsutilIdentity) Fingerprint(pid int) (string, error) {
+	return platformFingerprint(pid)
+}
+
+var defaultProcessIdentity ProcessIdentity = gopsutilIdentity{}
+
+// SetProcessIdentity overrides the ProcessIdentity used for fingerprinting.
+// Passing nil restores the default gopsutil-backed implementation.
+func SetProcessIdentity(pi ProcessIdentity) (restore func()) {
+	prev := defaultProcessIdentity
+	if pi != nil {
+		defaultProcessIdentity = pi
+	} else {
+		defaultProcessIdentity = gopsutilIdentity{}
+	}
+	return func() { defaultProcessIdentity = prev }
+}
+
+// fingerprintFn is the overridable hook isPIDReused and newLoggerAt use to
+// fingerprint a pid, mirroring the package's existing
+// processRunningCheck/processStartTimeFn convention.
+var fingerprintFn = func(pid int) (string, error) { return defaultProcessIdentity.Fingerprint(pid) }
+
+// hashFingerprint combines arbitrary identity facts (cgroup path, start
+// time, ...) into a short, comparable fingerprint string.
+func hashFingerprint(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// fallbackFingerprint is used by platforms with no cgroup/namespace concept
+// (Darwin, Windows): it hashes only the process start time, so it still
+// catches PID reuse via a clock-resolution-bounded signal, just without the
+// cgroup-level granularity identity_linux.go gets.
+func fallbackFingerprint(pid int) (string, error) {
+	start := getProcessStartTime(pid)
+	if start.IsZero() {
+		return "", fmt.Errorf("logger: could not determine start time for pid %d", pid)
+	}
+	return hashFingerprint(start.String()), nil
+}
+
+// writeFingerprintSidecar writes the creating process's fingerprint to
+// path's sidecar file. Errors are non-fatal: a log with no sidecar just
+// falls back to the mtime heuristic in isPIDReused.
+func writeFingerprintSidecar(path string) {
+	fp, err := fingerprintFn(os.Getpid())
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(fingerprintSidecarPath(path), []byte(fp), 0o644)
+}
+
+// readStoredFingerprint reads the fingerprint writeFingerprintSidecar
+// recorded for path, if any.
+func readStoredFingerprint(path string) (string, bool) {
+	data, err := readLogFileFn(fingerprintSidecarPath(path))
+	if err != nil || len(data) == 0 {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
@@ -0,0 +1,10 @@
+//go:build darwin
+
+package logger
+
+// platformFingerprint falls back to the process start time alone: Darwin
+// has no cgroup/namespace concept, so cross-container PID collisions aren't
+// a concern the way they are on Linux.
+func platformFingerprint(pid int) (string, error) {
+	return fallbackFingerprint(pid)
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+// platformFingerprint hashes /proc/<pid>/cgroup together with the process's
+// start time. The cgroup path differs across containers/namespaces sharing
+// the host PID space, so a PID reused by an unrelated process in a
+// different container is caught even when the host's PID counter wraps back
+// onto the same number.
+func platformFingerprint(pid int) (string, error) {
+	cgroup, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid)) // #nosec G304 -- /proc/<pid> path built from an int, not attacker-controlled input
+	if err != nil {
+		return fallbackFingerprint(pid)
+	}
+	start := getProcessStartTime(pid)
+	return hashFingerprint(string(cgroup), start.String()), nil
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+var fixedRecentTime = time.Now()
+
+func stubFingerprintFn(t *testing.T, fn func(int) (string, error)) {
+	t.Helper()
+	t.Cleanup(SetFingerprintFn(fn))
+}
+
+func TestIsPIDReusedPrefersFingerprintMismatch(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := createTempLog(t, tempDir, "codeagent-wrapper-555.log")
+	if err := os.WriteFile(fingerprintSidecarPath(logPath), []byte("host-a-fingerprint"), 0o644); err != nil {
+		t.Fatalf("failed to write fingerprint sidecar: %v", err)
+	}
+
+	stubFingerprintFn(t, func(int) (string, error) { return "host-b-fingerprint", nil })
+
+	if !isPIDReused(logPath, 555) {
+		t.Fatal("expected a fingerprint mismatch to report the pid as reused")
+	}
+}
+
+func TestIsPIDReusedTrustsMatchingFingerprint(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := createTempLog(t, tempDir, "codeagent-wrapper-556.log")
+	if err := os.WriteFile(fingerprintSidecarPath(logPath), []byte("same-fingerprint"), 0o644); err != nil {
+		t.Fatalf("failed to write fingerprint sidecar: %v", err)
+	}
+
+	stubFingerprintFn(t, func(int) (string, error) { return "same-fingerprint", nil })
+
+	if isPIDReused(logPath, 556) {
+		t.Fatal("expected a matching fingerprint to report the pid as not reused")
+	}
+}
+
+func TestIsPIDReusedFallsBackWhenFingerprintUnavailable(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := createTempLog(t, tempDir, "codeagent-wrapper-557.log")
+	if err := os.WriteFile(fingerprintSidecarPath(logPath), []byte("stored-fingerprint"), 0o644); err != nil {
+		t.Fatalf("failed to write fingerprint sidecar: %v", err)
+	}
+
+	stubFingerprintFn(t, func(int) (string, error) { return "", errors.New("cannot fingerprint") })
+	stubFileStat(t, func(string) (os.FileInfo, error) {
+		return fakeFileInfo{modTime: fixedRecentTime}, nil
+	})
+	stubProcessStartTime(t, func(int) time.Time { return fixedRecentTime.Add(-time.Hour) })
+
+	if isPIDReused(logPath, 557) {
+		t.Fatal("expected the mtime fallback to report the pid as still active")
+	}
+}
+
+func TestWriteFingerprintSidecarIsReadBackByNewLogger(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+	logger.Flush()
+
+	stored, ok := readStoredFingerprint(logger.Path())
+	if !ok || stored == "" {
+		t.Fatalf("expected a fingerprint sidecar to be written for a newly created logger, ok=%v stored=%q", ok, stored)
+	}
+}
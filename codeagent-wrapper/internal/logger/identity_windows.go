@@ -0,0 +1,9 @@
+//go:build windows
+
+package logger
+
+// platformFingerprint falls back to the process start time alone: Windows
+// has no /proc or cgroup equivalent this package can read.
+func platformFingerprint(pid int) (string, error) {
+	return fallbackFingerprint(pid)
+}
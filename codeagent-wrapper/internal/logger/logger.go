@@ -0,0 +1,611 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxCachedErrorEntries bounds the in-memory ring buffer of recent WARN/ERROR
+// messages kept by ExtractRecentErrors, independent of what's on disk.
+const maxCachedErrorEntries = 100
+
+// orphanFileAge is how stale a log file's last write must be before it's
+// treated as orphaned when the owning PID's start time can't be determined.
+const orphanFileAge = 24 * time.Hour
+
+type logEntry struct {
+	level  string
+	msg    string
+	at     time.Time
+	fields []Field
+}
+
+// textLine renders e in the historical FormatText shape, regardless of
+// fields (FormatText ignores them).
+func textLine(e logEntry) string {
+	return fmt.Sprintf("[%s] %s %s\n", e.at.Format(time.RFC3339), e.level, e.msg)
+}
+
+type flushRequest struct{ done chan struct{} }
+
+type closeRequest struct{ done chan error }
+
+// Logger writes leveled log lines to a per-process file in the background,
+// so callers never block on disk I/O. A nil *Logger is safe to call every
+// method on (all are no-ops / zero values), matching how the package treats
+// "no logger configured" elsewhere.
+type Logger struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	ch     chan interface{}
+	done   chan struct{}
+
+	rotation     RotationPolicy
+	writtenBytes atomic.Int64
+	format       Format
+
+	errMu      sync.Mutex
+	errSeq     int64
+	errEntries []cachedEntry
+
+	scanMu  sync.Mutex
+	errScan *errScanCache
+
+	sinks []*sinkWorker
+
+	// entryCh is the droppable queue log entries are enqueued into; ch
+	// above carries only control messages (flush/rotation/close), which
+	// must never be dropped. See queue_policy.go.
+	entryCh          chan logEntry
+	queuePolicy      QueuePolicy
+	entryDropped     atomic.Int64
+	lastFlushLatency atomic.Int64 // nanoseconds
+}
+
+// NewLogger creates a log file named "<WrapperName>-<pid>.log" in the
+// system temp directory and starts its background writer. opts can include
+// WithSink to fan entries out to additional destinations.
+func NewLogger(opts ...LoggerOption) (*Logger, error) {
+	return newLoggerAt(logFilePath(os.Getpid()), opts...)
+}
+
+// NewLoggerWithSuffix is like NewLogger but names the file
+// "<WrapperName>-<pid>-<suffix>.log", for loggers scoped to a single task.
+func NewLoggerWithSuffix(suffix string, opts ...LoggerOption) (*Logger, error) {
+	return newLoggerAt(logFilePathWithSuffix(os.Getpid(), suffix), opts...)
+}
+
+func logFilePath(pid int) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d.log", WrapperName, pid))
+}
+
+func logFilePathWithSuffix(pid int, suffix string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d-%s.log", WrapperName, pid, sanitizeLogSuffix(suffix)))
+}
+
+func newLoggerAt(path string, opts ...LoggerOption) (*Logger, error) {
+	info, statErr := os.Stat(path)
+	isNew := statErr != nil || info.Size() == 0
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open log file %s: %w", path, err)
+	}
+
+	l := &Logger{
+		path:    path,
+		file:    f,
+		writer:  bufio.NewWriter(f),
+		ch:      make(chan interface{}, 256),
+		entryCh: make(chan logEntry, defaultQueueCapacity),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if isNew {
+		writeFingerprintSidecar(path)
+	}
+	go l.run()
+	return l, nil
+}
+
+// run is the Logger's single writer goroutine. It selects between entryCh
+// (droppable log entries, batched into the bufio.Writer as they arrive) and
+// ch (control messages, which are never dropped): see queue_policy.go for
+// why entries and control messages live on separate channels.
+func (l *Logger) run() {
+	defer close(l.done)
+	for {
+		select {
+		case e := <-l.entryCh:
+			l.writeEntry(e)
+		case msg := <-l.ch:
+			switch m := msg.(type) {
+			case flushRequest:
+				start := time.Now()
+				l.drainEntries()
+				_ = l.writer.Flush()
+				for _, sw := range l.sinks {
+					_ = sw.flush()
+				}
+				l.lastFlushLatency.Store(int64(time.Since(start)))
+				close(m.done)
+			case rotationRequest:
+				l.rotation = m.policy
+				close(m.done)
+			case closeRequest:
+				l.drainEntries()
+				_ = l.writer.Flush()
+				for _, sw := range l.sinks {
+					_ = sw.close()
+				}
+				m.done <- l.file.Close()
+				return
+			}
+		}
+	}
+}
+
+// drainEntries writes every entry currently sitting in entryCh without
+// blocking, so a flush or close sees everything a producer enqueued
+// strictly before calling Flush/Close (by the time Flush()'s control
+// message reaches ch, any entry the same goroutine sent earlier is already
+// sitting in entryCh's buffer - see enqueueEntry).
+func (l *Logger) drainEntries() {
+	for {
+		select {
+		case e := <-l.entryCh:
+			l.writeEntry(e)
+		default:
+			return
+		}
+	}
+}
+
+func (l *Logger) writeEntry(e logEntry) {
+	line := l.formatLine(e)
+	l.writer.WriteString(line)
+	l.writtenBytes.Add(int64(len(line)))
+
+	if e.level == "WARN" || e.level == "ERROR" {
+		l.errMu.Lock()
+		l.errSeq++
+		entry := Entry{Time: e.at, Level: levelFromString(e.level), Message: e.msg, PID: os.Getpid()}
+		l.errEntries = append(l.errEntries, cachedEntry{seq: l.errSeq, entry: entry})
+		if len(l.errEntries) > maxCachedErrorEntries {
+			l.errEntries = l.errEntries[len(l.errEntries)-maxCachedErrorEntries:]
+		}
+		l.errMu.Unlock()
+	}
+
+	for _, sw := range l.sinks {
+		sw.enqueue(Entry{Time: e.at, Level: levelFromString(e.level), Message: e.msg, PID: os.Getpid()})
+	}
+
+	l.maybeRotate()
+}
+
+func (l *Logger) log(level, msg string) {
+	if l == nil {
+		return
+	}
+	l.enqueueEntry(logEntry{level: level, msg: msg, at: time.Now()})
+}
+
+func (l *Logger) Debug(msg string) { l.log("DEBUG", msg) }
+func (l *Logger) Info(msg string)  { l.log("INFO", msg) }
+func (l *Logger) Warn(msg string)  { l.log("WARN", msg) }
+func (l *Logger) Error(msg string) { l.log("ERROR", msg) }
+
+// Flush blocks until all log lines written so far are flushed to disk.
+func (l *Logger) Flush() {
+	if l == nil {
+		return
+	}
+	reply := make(chan struct{})
+	l.ch <- flushRequest{done: reply}
+	<-reply
+}
+
+// Close stops the background writer and closes the file. The file itself is
+// left on disk for post-mortem debugging; use RemoveLogFile to delete it.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	reply := make(chan error, 1)
+	l.ch <- closeRequest{done: reply}
+	err := <-reply
+	<-l.done
+	return err
+}
+
+// Path returns the log file path, or "" for a nil/zero-value Logger.
+func (l *Logger) Path() string {
+	if l == nil {
+		return ""
+	}
+	return l.path
+}
+
+// RemoveLogFile deletes the log file along with its fingerprint sidecar (see
+// writeFingerprintSidecar). Safe to call on a nil Logger or one whose path
+// is empty.
+func (l *Logger) RemoveLogFile() error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+	_ = removeLogFileFn(fingerprintSidecarPath(l.path))
+	return removeLogFileFn(l.path)
+}
+
+// ExtractRecentErrors returns up to maxEntries of the most recently cached
+// WARN/ERROR messages, oldest first. It returns nil for a nil/zero-value
+// Logger or a non-positive maxEntries. The cache holds the plain message
+// text regardless of l's Format, so callers don't need to know or detect
+// whether the underlying file is FormatText or FormatJSON.
+//
+// filter is optional; when given, its first value is a comma-separated
+// list of glob patterns (see matchesAnyFilter) narrowing the result to
+// messages matching at least one pattern, so a caller can scope the tail to
+// one subsystem ("worker/*") without post-filtering the whole slice. An
+// empty filter behaves as if none were given; blank or malformed patterns
+// in the list are skipped, so a filter made only of them matches nothing.
+func (l *Logger) ExtractRecentErrors(maxEntries int, filter ...string) []string {
+	if l == nil || l.path == "" || maxEntries <= 0 {
+		return nil
+	}
+
+	var pattern string
+	if len(filter) > 0 {
+		pattern = filter[0]
+	}
+
+	l.errMu.Lock()
+	cached := make([]cachedEntry, len(l.errEntries))
+	copy(cached, l.errEntries)
+	l.errMu.Unlock()
+
+	entries := make([]string, 0, len(cached))
+	for _, c := range cached {
+		if pattern == "" || matchesAnyFilter(c.entry.Message, pattern) {
+			entries = append(entries, c.entry.Message)
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	n := maxEntries
+	if n > len(entries) {
+		n = len(entries)
+	}
+	out := make([]string, n)
+	copy(out, entries[len(entries)-n:])
+	return out
+}
+
+// sanitizeLogSuffix makes suffix safe to embed in a log file name by
+// replacing any character outside [A-Za-z0-9_.-] with "_". It deliberately
+// does not trim or collapse runs of dots/dashes, so distinct suffixes never
+// collide onto the same file name.
+func sanitizeLogSuffix(suffix string) string {
+	var b strings.Builder
+	for _, r := range suffix {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// SanitizeLogSuffix is the exported form of sanitizeLogSuffix.
+func SanitizeLogSuffix(suffix string) string { return sanitizeLogSuffix(suffix) }
+
+// parsePIDFromLog extracts the PID from a "<WrapperName>-<pid>.log" or
+// "<WrapperName>-<pid>-<suffix>.log" file name.
+func parsePIDFromLog(path string) (int, bool) {
+	base := filepath.Base(path)
+	prefix := WrapperName + "-"
+	if !strings.HasPrefix(base, prefix) {
+		return 0, false
+	}
+	rest := strings.TrimPrefix(base, prefix)
+	if !strings.HasSuffix(rest, ".log") {
+		return 0, false
+	}
+	rest = strings.TrimSuffix(rest, ".log")
+	if idx := strings.Index(rest, "-"); idx >= 0 {
+		rest = rest[:idx]
+	}
+	if rest == "" {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(rest)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// isPIDReused reports whether pid likely belongs to a different process than
+// the one that last wrote path. If path has a stored fingerprint (see
+// identity.go) and pid's current fingerprint can be determined, a mismatch
+// is a strong, namespace-aware reuse signal. Otherwise it falls back to the
+// mtime heuristic: either the file is old enough that we no longer trust an
+// unknown start time, or the process's start time is after the file's last
+// write.
+func isPIDReused(path string, pid int) bool {
+	if stored, ok := readStoredFingerprint(path); ok {
+		if current, err := fingerprintFn(pid); err == nil && current != "" {
+			return current != stored
+		}
+	}
+
+	info, err := fileStatFn(path)
+	if err != nil {
+		return false
+	}
+
+	modTime := info.ModTime()
+	startTime := processStartTimeFn(pid)
+	if startTime.IsZero() {
+		return time.Since(modTime) > orphanFileAge
+	}
+	return startTime.After(modTime)
+}
+
+// isUnsafeFile guards removal against symlinks and paths that, once
+// symlinks are resolved, escape tempDir.
+func isUnsafeFile(path, tempDir string) (bool, string) {
+	info, err := fileStatFn(path)
+	if err != nil {
+		return true, "cannot stat file"
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return true, "refusing to delete symlink"
+	}
+
+	resolved, err := evalSymlinksFn(path)
+	if err != nil {
+		return true, "cannot resolve path"
+	}
+	absTempDir, err := filepath.Abs(tempDir)
+	if err != nil {
+		return true, "cannot resolve tempDir"
+	}
+	rel, err := filepath.Rel(absTempDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return true, "file is outside tempDir"
+	}
+	return false, ""
+}
+
+// removeIfUnchanged re-stats path and only removes it if its mtime and size
+// still match info (the stat taken at glob time), then delegates the
+// removal itself to removeLogFileFn. This closes the race where a process
+// rotates file (or simply reopens it) between cleanupOldLogs globbing it
+// and getting around to deleting it: the stale snapshot no longer matches,
+// so the file that now lives at path is left alone.
+func removeIfUnchanged(path string, info os.FileInfo) error {
+	if info == nil {
+		// No snapshot was taken (the glob-time stat failed); fall back to
+		// removing unconditionally rather than refusing to ever remove it.
+		return removeLogFileFn(path)
+	}
+	cur, err := fileStatFn(path)
+	if err != nil {
+		return err
+	}
+	if !cur.ModTime().Equal(info.ModTime()) || cur.Size() != info.Size() {
+		return errFileChangedSinceScan
+	}
+	return removeLogFileFn(path)
+}
+
+// removeFingerprintSidecar best-effort deletes path's fingerprint sidecar
+// (see writeFingerprintSidecar) once path itself has been removed.
+// cleanupOldLogs' classification loop skips sidecar files outright - they carry no
+// PID/timestamp of their own to classify - so this is the only place that
+// ever removes them; without it every log cleanupOldLogs deletes leaves its
+// ".fp" behind forever. Errors are ignored: most deleted files (rotated
+// backups) never had a sidecar to begin with.
+func removeFingerprintSidecar(path string) {
+	_ = removeLogFileFn(fingerprintSidecarPath(path))
+}
+
+// errFileChangedSinceScan signals removeIfUnchanged's stat-then-remove
+// guard tripped; cleanupOldLogs treats it the same as any other file it
+// can't safely remove, by counting the file as kept rather than erroring.
+var errFileChangedSinceScan = errors.New("logger: file changed since cleanup scan")
+
+// CleanupStats summarizes a cleanupOldLogs run.
+type CleanupStats struct {
+	Scanned      int
+	Deleted      int
+	Kept         int
+	Errors       int
+	DeletedFiles []string
+	KeptFiles    []string
+}
+
+var (
+	processRunningCheck = isProcessRunning
+	processStartTimeFn  = getProcessStartTime
+	removeLogFileFn     = os.Remove
+	globLogFiles        = filepath.Glob
+	fileStatFn          = os.Lstat
+	evalSymlinksFn      = filepath.EvalSymlinks
+)
+
+// defaultBackupMaxAge and defaultMaxBackupsPerPID bound rotated backups
+// found during a directory-wide cleanupOldLogs scan. The scan has no way to
+// recover the RotationPolicy each backup's owning process configured (it
+// only sees file names), so it applies these defaults uniformly rather than
+// guessing per file.
+const (
+	defaultBackupMaxAge     = 7 * 24 * time.Hour
+	defaultMaxBackupsPerPID = 5
+)
+
+// cleanupOldLogs removes log files belonging to processes that are no
+// longer running (or whose PID has clearly been reused by a different
+// process since the file was last written), while leaving the active
+// process's own log and any file it can't safely classify or remove.
+// Rotated backups (see RotationPolicy) are pruned separately by
+// defaultMaxBackupsPerPID/defaultBackupMaxAge regardless of whether their
+// owning PID is still running, since a backup is historical the moment
+// rotate() creates it.
+func cleanupOldLogs() (CleanupStats, error) {
+	var stats CleanupStats
+
+	tempDir := os.TempDir()
+
+	unlock, ok := tryLockTempDir(tempDir)
+	if !ok {
+		return CleanupStats{}, ErrCleanupInProgress
+	}
+	defer unlock()
+
+	files, err := globLogFiles(filepath.Join(tempDir, WrapperName+"-*.log*"))
+	if err != nil {
+		return stats, fmt.Errorf("glob log files: %w", err)
+	}
+
+	// snapshot captures each candidate's mtime/size at glob time, so a file
+	// rotated (renamed away, replaced by a fresh one at the same path)
+	// between the glob and the eventual os.Remove is never deleted out from
+	// under the process that just rotated it; see removeIfUnchanged.
+	snapshot := make(map[string]os.FileInfo, len(files))
+	for _, file := range files {
+		if info, err := fileStatFn(file); err == nil {
+			snapshot[file] = info
+		}
+	}
+
+	backupsByPID := make(map[int][]string)
+	var active []string
+	for _, file := range files {
+		if strings.HasSuffix(file, fingerprintSidecarSuffix) {
+			continue
+		}
+		if _, isBackup := backupTimestamp(file); isBackup {
+			pid, _ := parsePIDFromLog(file)
+			backupsByPID[pid] = append(backupsByPID[pid], file)
+			continue
+		}
+		active = append(active, file)
+	}
+
+	var errs []error
+
+	for _, paths := range backupsByPID {
+		sort.Slice(paths, func(i, j int) bool {
+			ti, _ := backupTimestamp(paths[i])
+			tj, _ := backupTimestamp(paths[j])
+			return ti > tj // newest first
+		})
+
+		now := time.Now()
+		for i, file := range paths {
+			stats.Scanned++
+
+			ts, _ := backupTimestamp(file)
+			tooOld := now.Sub(time.Unix(0, ts)) > defaultBackupMaxAge
+			tooMany := i >= defaultMaxBackupsPerPID
+			if !tooOld && !tooMany {
+				stats.Kept++
+				stats.KeptFiles = append(stats.KeptFiles, file)
+				continue
+			}
+
+			if unsafe, _ := isUnsafeFile(file, tempDir); unsafe {
+				stats.Kept++
+				stats.KeptFiles = append(stats.KeptFiles, file)
+				continue
+			}
+
+			if err := removeIfUnchanged(file, snapshot[file]); err != nil {
+				if errors.Is(err, errFileChangedSinceScan) {
+					stats.Kept++
+					stats.KeptFiles = append(stats.KeptFiles, file)
+					continue
+				}
+				stats.Errors++
+				errs = append(errs, err)
+				continue
+			}
+			stats.Deleted++
+			stats.DeletedFiles = append(stats.DeletedFiles, file)
+			removeFingerprintSidecar(file)
+		}
+	}
+
+	for _, file := range active {
+		stats.Scanned++
+
+		pid, ok := parsePIDFromLog(file)
+		if !ok {
+			stats.Kept++
+			stats.KeptFiles = append(stats.KeptFiles, file)
+			continue
+		}
+
+		if unsafe, _ := isUnsafeFile(file, tempDir); unsafe {
+			stats.Kept++
+			stats.KeptFiles = append(stats.KeptFiles, file)
+			continue
+		}
+
+		shouldDelete := true
+		if processRunningCheck(pid) {
+			shouldDelete = isPIDReused(file, pid)
+		}
+		if !shouldDelete {
+			stats.Kept++
+			stats.KeptFiles = append(stats.KeptFiles, file)
+			continue
+		}
+
+		if err := removeIfUnchanged(file, snapshot[file]); err != nil {
+			if errors.Is(err, errFileChangedSinceScan) {
+				stats.Kept++
+				stats.KeptFiles = append(stats.KeptFiles, file)
+				continue
+			}
+			stats.Errors++
+			errs = append(errs, err)
+			continue
+		}
+		stats.Deleted++
+		stats.DeletedFiles = append(stats.DeletedFiles, file)
+		removeFingerprintSidecar(file)
+	}
+
+	if len(errs) > 0 {
+		return stats, errors.Join(errs...)
+	}
+	return stats, nil
+}
+
+// CleanupOldLogs is the exported form of cleanupOldLogs.
+func CleanupOldLogs() (CleanupStats, error) { return cleanupOldLogs() }
@@ -202,6 +202,41 @@ func TestLoggerCleanupOldLogsRemovesOrphans(t *testing.T) {
 	}
 }
 
+func TestLoggerCleanupOldLogsRemovesFingerprintSidecar(t *testing.T) {
+	tempDir := setTempDirEnv(t, t.TempDir())
+
+	orphan := createTempLog(t, tempDir, "codeagent-wrapper-111.log")
+	sidecar := createTempLog(t, tempDir, "codeagent-wrapper-111.log.fp")
+	running := createTempLog(t, tempDir, "codeagent-wrapper-222.log")
+	runningSidecar := createTempLog(t, tempDir, "codeagent-wrapper-222.log.fp")
+
+	runningPIDs := map[int]bool{222: true}
+	stubProcessRunning(t, func(pid int) bool { return runningPIDs[pid] })
+	stubProcessStartTime(t, func(pid int) time.Time {
+		if runningPIDs[pid] {
+			return time.Now().Add(-1 * time.Hour)
+		}
+		return time.Time{}
+	})
+
+	if _, err := cleanupOldLogs(); err != nil {
+		t.Fatalf("cleanupOldLogs() unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Fatalf("expected orphan log %s to be removed, err=%v", orphan, err)
+	}
+	if _, err := os.Stat(sidecar); !os.IsNotExist(err) {
+		t.Fatalf("expected orphan's fingerprint sidecar %s to be removed, err=%v", sidecar, err)
+	}
+	if _, err := os.Stat(running); err != nil {
+		t.Fatalf("expected running log %s to remain, err=%v", running, err)
+	}
+	if _, err := os.Stat(runningSidecar); err != nil {
+		t.Fatalf("expected running log's fingerprint sidecar %s to remain, err=%v", runningSidecar, err)
+	}
+}
+
 func TestLoggerCleanupOldLogsHandlesInvalidNamesAndErrors(t *testing.T) {
 	tempDir := setTempDirEnv(t, t.TempDir())
 
@@ -558,12 +593,20 @@ func TestLoggerPathAndRemove(t *testing.T) {
 		t.Fatalf("Close() error = %v", err)
 	}
 
+	sidecar := fingerprintSidecarPath(path)
+	if _, err := os.Stat(sidecar); err != nil {
+		t.Fatalf("expected fingerprint sidecar %s to exist before removal, err=%v", sidecar, err)
+	}
+
 	if err := logger.RemoveLogFile(); err != nil {
 		t.Fatalf("RemoveLogFile() error = %v", err)
 	}
 	if _, err := os.Stat(path); !os.IsNotExist(err) {
 		t.Fatalf("expected log file to be removed, err=%v", err)
 	}
+	if _, err := os.Stat(sidecar); !os.IsNotExist(err) {
+		t.Fatalf("expected fingerprint sidecar %s to be removed, err=%v", sidecar, err)
+	}
 
 	var nilLogger *Logger
 	if nilLogger.Path() != "" {
@@ -901,3 +944,37 @@ func TestErrorEntriesMaxLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractRecentErrorsFilterMatchesPathPrefix(t *testing.T) {
+	logger, err := NewLoggerWithSuffix("filter-test")
+	if err != nil {
+		t.Fatalf("NewLoggerWithSuffix() error = %v", err)
+	}
+	defer logger.Close()
+	defer func() { _ = logger.RemoveLogFile() }()
+
+	logger.Error("worker/build/failed")
+	logger.Error("worker/test/flaky")
+	logger.Error("scheduler/queue/stuck")
+	logger.Flush()
+
+	result := logger.ExtractRecentErrors(10, "worker/*")
+	if len(result) != 2 {
+		t.Fatalf("ExtractRecentErrors(10, \"worker/*\") expected 2 entries, got %d: %v", len(result), result)
+	}
+	for _, e := range result {
+		if !strings.HasPrefix(e, "worker/") {
+			t.Fatalf("expected only worker/* entries, got %q", e)
+		}
+	}
+
+	all := logger.ExtractRecentErrors(10)
+	if len(all) != 3 {
+		t.Fatalf("ExtractRecentErrors(10) without a filter expected 3 entries, got %d", len(all))
+	}
+
+	malformed := logger.ExtractRecentErrors(10, "[")
+	if len(malformed) != 0 {
+		t.Fatalf("a malformed pattern should match nothing, got %v", malformed)
+	}
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// OTLPHTTPSink batches entries and POSTs them, shaped as minimal OTLP logs
+// JSON (resourceLogs/scopeLogs/logRecords), to an OTLP/HTTP collector
+// endpoint. It mirrors HTTPHook's batch-by-size-or-interval design; unlike
+// HTTPHook it implements Sink (Write, not Fire) for use with WithSink.
+type OTLPHTTPSink struct {
+	url           string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []Entry
+	timer   *time.Timer
+}
+
+const (
+	defaultOTLPBatchSize     = 20
+	defaultOTLPFlushInterval = 5 * time.Second
+)
+
+// NewOTLPHTTPSink returns a Sink that batches entries and posts them to an
+// OTLP/HTTP collector's logs endpoint (e.g. ".../v1/logs").
+func NewOTLPHTTPSink(url string) *OTLPHTTPSink {
+	return &OTLPHTTPSink{
+		url:           url,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		batchSize:     defaultOTLPBatchSize,
+		flushInterval: defaultOTLPFlushInterval,
+	}
+}
+
+func (s *OTLPHTTPSink) Write(entry Entry) error {
+	s.mu.Lock()
+	s.pending = append(s.pending, entry)
+	full := len(s.pending) >= s.batchSize
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.flushInterval, func() { _ = s.Flush() })
+	}
+	s.mu.Unlock()
+
+	if full {
+		return s.Flush()
+	}
+	return nil
+}
+
+// Flush sends any buffered entries immediately. The batch is dropped on
+// failure rather than retried forever, so one unreachable collector can't
+// grow unbounded memory; sinkWorker's drop-oldest queue already bounds
+// memory upstream of this.
+func (s *OTLPHTTPSink) Flush() error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpLogsPayload(batch))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("logger: otlp http sink: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close flushes any remaining buffered entries.
+func (s *OTLPHTTPSink) Close() error { return s.Flush() }
+
+// otlpLogsPayload shapes batch into the minimal OTLP logs JSON structure a
+// collector's otlphttp receiver expects.
+func otlpLogsPayload(batch []Entry) map[string]interface{} {
+	records := make([]map[string]interface{}, len(batch))
+	for i, e := range batch {
+		records[i] = map[string]interface{}{
+			"timeUnixNano": e.Time.UnixNano(),
+			"severityText": e.Level.String(),
+			"body":         map[string]interface{}{"stringValue": e.Message},
+			"attributes": []map[string]interface{}{
+				{"key": "pid", "value": map[string]interface{}{"intValue": e.PID}},
+			},
+		}
+	}
+	return map[string]interface{}{
+		"resourceLogs": []map[string]interface{}{
+			{
+				"scopeLogs": []map[string]interface{}{
+					{"logRecords": records},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// cachedEntry pairs a monotonically increasing sequence number with the
+// cached Entry it belongs to. The sequence number is never reused within a
+// Logger's lifetime (even once old entries are evicted from errEntries by
+// maxCachedErrorEntries), so it doubles as ListEntries' opaque cursor.
+type cachedEntry struct {
+	seq   int64
+	entry Entry
+}
+
+// ListEntriesOptions configures a ListEntries call.
+type ListEntriesOptions struct {
+	// After is an opaque cursor from a previous ListEntries call's
+	// nextCursor. The zero value starts from the oldest cached entry (or
+	// the newest, if Reverse).
+	After string
+	// Limit bounds the page size; a non-positive Limit returns no entries.
+	Limit int
+	// Reverse walks from the most recently cached entry backward instead
+	// of the oldest forward.
+	Reverse bool
+}
+
+// cursorFor renders seq as ListEntries' cursor: fixed-width so cursors sort
+// the same way as the sequence numbers they encode.
+func cursorFor(seq int64) string {
+	return fmt.Sprintf("%020d", seq)
+}
+
+func parseCursor(cursor string) (seq int64, ok bool) {
+	if cursor == "" {
+		return 0, false
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// ListEntries returns one page of this Logger's cached WARN/ERROR entries
+// (the same in-memory cache ExtractRecentErrors reads), honoring
+// opts.After/opts.Limit/opts.Reverse. nextCursor is the After to pass for
+// the following page, or "" once the walk is exhausted. The historical
+// "last N, oldest first" behavior of ExtractRecentErrors is equivalent to
+// ListEntries(ListEntriesOptions{Limit: N, Reverse: true}) with the
+// returned slice reversed back into chronological order.
+func (l *Logger) ListEntries(opts ListEntriesOptions) (entries []Entry, nextCursor string) {
+	if l == nil || opts.Limit <= 0 {
+		return nil, ""
+	}
+
+	after, hasAfter := parseCursor(opts.After)
+
+	l.errMu.Lock()
+	all := make([]cachedEntry, len(l.errEntries))
+	copy(all, l.errEntries)
+	l.errMu.Unlock()
+
+	if len(all) == 0 {
+		return nil, ""
+	}
+
+	var page []cachedEntry
+	if opts.Reverse {
+		for i := len(all) - 1; i >= 0 && len(page) < opts.Limit; i-- {
+			if hasAfter && all[i].seq >= after {
+				continue
+			}
+			page = append(page, all[i])
+		}
+	} else {
+		for _, c := range all {
+			if hasAfter && c.seq <= after {
+				continue
+			}
+			if len(page) >= opts.Limit {
+				break
+			}
+			page = append(page, c)
+		}
+	}
+
+	if len(page) == 0 {
+		return nil, ""
+	}
+
+	entries = make([]Entry, len(page))
+	for i, c := range page {
+		entries[i] = c.entry
+	}
+
+	lastSeq := page[len(page)-1].seq
+	exhausted := lastSeq == all[len(all)-1].seq
+	if opts.Reverse {
+		exhausted = lastSeq == all[0].seq
+	}
+	if !exhausted {
+		nextCursor = cursorFor(lastSeq)
+	}
+	return entries, nextCursor
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestListEntriesPaginatesForwardInFixedSizePages(t *testing.T) {
+	logger, err := NewLoggerWithSuffix("list-entries-paginate")
+	if err != nil {
+		t.Fatalf("NewLoggerWithSuffix() error = %v", err)
+	}
+	defer logger.Close()
+	defer func() { _ = logger.RemoveLogFile() }()
+
+	for i := 1; i <= 150; i++ {
+		if i%2 == 0 {
+			logger.Error(fmt.Sprintf("error-%03d", i))
+		} else {
+			logger.Warn(fmt.Sprintf("warn-%03d", i))
+		}
+	}
+	logger.Flush()
+
+	var walked []Entry
+	cursor := ""
+	for {
+		page, next := logger.ListEntries(ListEntriesOptions{After: cursor, Limit: 17})
+		if len(page) == 0 {
+			break
+		}
+		walked = append(walked, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(walked) != 100 {
+		t.Fatalf("expected 100 cached entries across pages, got %d", len(walked))
+	}
+	if walked[0].Message != "warn-051" {
+		t.Fatalf("first walked entry = %q, want warn-051", walked[0].Message)
+	}
+	if walked[len(walked)-1].Message != "error-150" {
+		t.Fatalf("last walked entry = %q, want error-150", walked[len(walked)-1].Message)
+	}
+
+	// The historical "last N, oldest first" tail is reimplementable as
+	// Limit:N Reverse:true with the result slice reversed back.
+	tailDesc, _ := logger.ListEntries(ListEntriesOptions{Limit: 100, Reverse: true})
+	tail := make([]string, len(tailDesc))
+	for i, e := range tailDesc {
+		tail[len(tailDesc)-1-i] = e.Message
+	}
+	want := logger.ExtractRecentErrors(100)
+	if len(tail) != len(want) {
+		t.Fatalf("reconstructed tail length = %d, want %d", len(tail), len(want))
+	}
+	for i := range want {
+		if tail[i] != want[i] {
+			t.Fatalf("reconstructed tail[%d] = %q, want %q", i, tail[i], want[i])
+		}
+	}
+}
+
+func TestListEntriesConcurrentAppendsDoNotSkipOrDuplicate(t *testing.T) {
+	logger, err := NewLoggerWithSuffix("list-entries-concurrent")
+	if err != nil {
+		t.Fatalf("NewLoggerWithSuffix() error = %v", err)
+	}
+	defer logger.Close()
+	defer func() { _ = logger.RemoveLogFile() }()
+
+	const total = 80
+	const seeded = total / 2
+
+	for i := 0; i < seeded; i++ {
+		logger.Error(fmt.Sprintf("seed-%03d", i))
+	}
+	logger.Flush()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := seeded; i < total; i++ {
+			logger.Error(fmt.Sprintf("seed-%03d", i))
+		}
+	}()
+
+	seen := make(map[string]int)
+	cursor := ""
+	for {
+		page, next := logger.ListEntries(ListEntriesOptions{After: cursor, Limit: 5})
+		if len(page) == 0 {
+			break
+		}
+		for _, e := range page {
+			seen[e.Message]++
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	wg.Wait()
+
+	for msg, count := range seen {
+		if count != 1 {
+			t.Fatalf("entry %q observed %d times while paginating, want exactly once", msg, count)
+		}
+	}
+
+	// Every seeded entry was written (and Flushed) before pagination began,
+	// so none of them may be skipped regardless of how the concurrent
+	// writer interleaves with the walk.
+	for i := 0; i < seeded; i++ {
+		msg := fmt.Sprintf("seed-%03d", i)
+		if seen[msg] != 1 {
+			t.Fatalf("seeded entry %q was skipped during pagination", msg)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package logger
+
+// defaultQueueCapacity bounds a Logger's entryCh when WithQueuePolicy isn't
+// used to override it, matching the capacity the single shared channel had
+// before entries and control messages were split onto separate channels.
+const defaultQueueCapacity = 256
+
+// QueuePolicy selects what a Logger does when its entry queue (entryCh) is
+// full at enqueue time.
+type QueuePolicy int
+
+const (
+	// PolicyBlock makes Info/Warn/Error/*Fields block until the queue has
+	// room, the historical (and still default) behavior: no entry is ever
+	// silently lost, at the cost of a slow sink/disk backing up producers.
+	PolicyBlock QueuePolicy = iota
+	// PolicyDropOldest discards the oldest still-queued entry to make room
+	// for the new one, favoring recency - useful when only the latest
+	// state matters and a producer must never block on logging.
+	PolicyDropOldest
+	// PolicyDropNewest discards the incoming entry instead, leaving
+	// whatever is already queued untouched - useful when earlier context
+	// (e.g. the entries leading up to a crash) matters more than the
+	// latest one.
+	PolicyDropNewest
+)
+
+// WithQueuePolicy configures how a Logger's entry queue behaves under
+// backpressure. capacity overrides the queue's buffer size; a non-positive
+// capacity keeps defaultQueueCapacity. Like other LoggerOptions it must be
+// passed to NewLogger/NewLoggerWithSuffix/NewLoggerWithFormat - the queue is
+// sized once at construction and not resized afterward.
+func WithQueuePolicy(policy QueuePolicy, capacity int) LoggerOption {
+	return func(l *Logger) {
+		l.queuePolicy = policy
+		if capacity > 0 {
+			l.entryCh = make(chan logEntry, capacity)
+		}
+	}
+}
+
+// enqueueEntry applies l.queuePolicy to e. PolicyDropOldest's two-step dance
+// (try enqueue, evict one on failure, retry) mirrors sinkWorker.enqueue's
+// identical non-blocking approach for the same reason: a channel gives no
+// way to remove a middle element, so the only way to "drop the oldest" is
+// to receive it off the front and make room for the new tail.
+func (l *Logger) enqueueEntry(e logEntry) {
+	switch l.queuePolicy {
+	case PolicyDropNewest:
+		select {
+		case l.entryCh <- e:
+		default:
+			l.entryDropped.Add(1)
+		}
+	case PolicyDropOldest:
+		select {
+		case l.entryCh <- e:
+			return
+		default:
+		}
+		select {
+		case <-l.entryCh:
+			l.entryDropped.Add(1)
+		default:
+		}
+		select {
+		case l.entryCh <- e:
+		default:
+			l.entryDropped.Add(1)
+		}
+	default: // PolicyBlock
+		l.entryCh <- e
+	}
+}
+
+// QueueStats is a point-in-time view of a Logger's own entry queue, as
+// opposed to SinkStats which covers sinks registered via WithSink.
+type QueueStats struct {
+	// Depth is how many entries are currently buffered in entryCh.
+	Depth int
+	// TotalBytesWritten is the cumulative size of every line written to
+	// the log file so far (pre-rotation truncation resets this to 0, same
+	// as the rotation accounting it shares).
+	TotalBytesWritten int64
+	// Dropped counts entries discarded by PolicyDropOldest/PolicyDropNewest
+	// since construction; always 0 under PolicyBlock.
+	Dropped int64
+	// LastFlushLatency is how long the most recent Flush() call took to
+	// drain the queue and flush the underlying writer/sinks, or 0 if
+	// Flush has never been called.
+	LastFlushLatency int64 // nanoseconds; see time.Duration(v)
+}
+
+// QueueStats returns the current QueueStats for l, or the zero value for a
+// nil Logger.
+func (l *Logger) QueueStats() QueueStats {
+	if l == nil {
+		return QueueStats{}
+	}
+	return QueueStats{
+		Depth:             len(l.entryCh),
+		TotalBytesWritten: l.writtenBytes.Load(),
+		Dropped:           l.entryDropped.Load(),
+		LastFlushLatency:  l.lastFlushLatency.Load(),
+	}
+}
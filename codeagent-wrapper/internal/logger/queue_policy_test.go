@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"os"
+	"testing"
+)
+
+// TestEnqueueEntryDropPolicies exercises enqueueEntry directly against a
+// Logger with no run() goroutine draining entryCh, so a full queue is
+// deterministic rather than racing a consumer.
+func TestEnqueueEntryDropPolicies(t *testing.T) {
+	t.Run("DropNewest discards the incoming entry", func(t *testing.T) {
+		l := &Logger{queuePolicy: PolicyDropNewest, entryCh: make(chan logEntry, 1)}
+		l.enqueueEntry(logEntry{msg: "first"})
+		l.enqueueEntry(logEntry{msg: "second"})
+
+		if got := l.entryDropped.Load(); got != 1 {
+			t.Fatalf("Dropped = %d, want 1", got)
+		}
+		if got := <-l.entryCh; got.msg != "first" {
+			t.Fatalf("queued entry = %q, want %q (oldest should survive)", got.msg, "first")
+		}
+	})
+
+	t.Run("DropOldest evicts the queued entry to admit the new one", func(t *testing.T) {
+		l := &Logger{queuePolicy: PolicyDropOldest, entryCh: make(chan logEntry, 1)}
+		l.enqueueEntry(logEntry{msg: "first"})
+		l.enqueueEntry(logEntry{msg: "second"})
+
+		if got := l.entryDropped.Load(); got != 1 {
+			t.Fatalf("Dropped = %d, want 1", got)
+		}
+		if got := <-l.entryCh; got.msg != "second" {
+			t.Fatalf("queued entry = %q, want %q (newest should survive)", got.msg, "second")
+		}
+	})
+}
+
+func TestWithQueuePolicyBlockNeverDrops(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 50; i++ {
+		logger.Info("burst")
+	}
+	logger.Flush()
+
+	if stats := logger.QueueStats(); stats.Dropped != 0 {
+		t.Fatalf("expected default PolicyBlock to never drop, got Dropped=%d", stats.Dropped)
+	}
+}
+
+func TestQueueStatsReportsBytesWrittenAndFlushLatency(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	logger.Flush()
+
+	stats := logger.QueueStats()
+	if stats.TotalBytesWritten == 0 {
+		t.Fatalf("expected non-zero TotalBytesWritten after a flushed write, got %+v", stats)
+	}
+	if stats.LastFlushLatency < 0 {
+		t.Fatalf("expected non-negative LastFlushLatency, got %d", stats.LastFlushLatency)
+	}
+
+	data, err := os.ReadFile(logger.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected the log file to contain the flushed entry")
+	}
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotationPolicy configures size/age-bounded rotation for a Logger. The
+// zero value disables rotation (the historical, unbounded-file behavior).
+type RotationPolicy struct {
+	// MaxSize is the file size in bytes at which the active log is rotated
+	// out. MaxSize <= 0 disables rotation entirely.
+	MaxSize int64
+	// MaxBackups caps how many rotated backups for this Logger's PID are
+	// kept; older ones are pruned on the next rotation. <= 0 means
+	// unlimited.
+	MaxBackups int
+	// MaxAge prunes rotated backups older than this on the next rotation.
+	// <= 0 means unlimited.
+	MaxAge time.Duration
+	// Compress gzips a rotated backup in the background after renaming it.
+	Compress bool
+}
+
+// backupTimestampRe matches the pure-digit nanosecond timestamp rotate()
+// appends to a backup's file name, distinguishing rotated backups from a
+// Logger's active file (including one created via NewLoggerWithSuffix,
+// whose suffix is sanitized but not required to be all-digit).
+var backupTimestampRe = regexp.MustCompile(`^\d{15,}$`)
+
+type rotationRequest struct {
+	policy RotationPolicy
+	done   chan struct{}
+}
+
+// SetRotationPolicy configures rotation for l. It takes effect for
+// subsequent writes; it does not retroactively rotate an oversized file.
+func (l *Logger) SetRotationPolicy(policy RotationPolicy) {
+	if l == nil {
+		return
+	}
+	reply := make(chan struct{})
+	l.ch <- rotationRequest{policy: policy, done: reply}
+	<-reply
+}
+
+// maybeRotate rotates the active file once it has grown past the
+// configured MaxSize, renaming it to "<WrapperName>-<pid>-<nanos>.log" and
+// opening a fresh file at the original path. Failures are treated as
+// non-fatal: the logger keeps writing to whatever file handle it has.
+func (l *Logger) maybeRotate() {
+	if l.rotation.MaxSize <= 0 || l.writtenBytes.Load() < l.rotation.MaxSize {
+		return
+	}
+
+	_ = l.writer.Flush()
+	if err := l.file.Close(); err != nil {
+		return
+	}
+
+	dir := filepath.Dir(l.path)
+	backupPath := filepath.Join(dir, fmt.Sprintf("%s-%d-%d.log", WrapperName, os.Getpid(), time.Now().UnixNano()))
+
+	if err := os.Rename(l.path, backupPath); err != nil {
+		// Couldn't rotate (e.g. cross-device); reopen the original file so
+		// logging can continue, oversized or not.
+		if f, ferr := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); ferr == nil {
+			l.file = f
+			l.writer = bufio.NewWriter(f)
+		}
+		return
+	}
+
+	if l.rotation.Compress {
+		go compressLogFile(backupPath)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return
+	}
+	l.file = f
+	l.writer = bufio.NewWriter(f)
+	l.writtenBytes.Store(0)
+
+	pruneBackupsForPID(dir, os.Getpid(), l.rotation.MaxBackups, l.rotation.MaxAge)
+}
+
+// compressLogFile gzips path in place, removing the uncompressed file only
+// once the compressed copy has been written successfully.
+func compressLogFile(path string) {
+	src, err := os.Open(path) // #nosec G304 -- rotated log file this process just created
+	if err != nil {
+		return
+	}
+	defer src.Close()
+
+	dstPath := path + ".gz"
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, copyErr := io.Copy(gz, src)
+	closeErr := gz.Close()
+	_ = dst.Close()
+
+	if copyErr != nil || closeErr != nil {
+		_ = os.Remove(dstPath)
+		return
+	}
+	_ = os.Remove(path)
+}
+
+// pruneBackupsForPID removes rotated backups belonging to pid under dir
+// that exceed maxBackups (keeping the most recent) or maxAge. maxBackups
+// and maxAge <= 0 mean "no limit" for that dimension.
+func pruneBackupsForPID(dir string, pid int, maxBackups int, maxAge time.Duration) {
+	if maxBackups <= 0 && maxAge <= 0 {
+		return
+	}
+
+	matches, err := globLogFiles(filepath.Join(dir, fmt.Sprintf("%s-%d-*.log*", WrapperName, pid)))
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, path := range matches {
+		if _, isBackup := backupTimestamp(path); isBackup {
+			backups = append(backups, path)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		ti, _ := backupTimestamp(backups[i])
+		tj, _ := backupTimestamp(backups[j])
+		return ti > tj // newest first
+	})
+
+	now := time.Now()
+	for i, path := range backups {
+		ts, _ := backupTimestamp(path)
+		tooOld := maxAge > 0 && now.Sub(time.Unix(0, ts)) > maxAge
+		tooMany := maxBackups > 0 && i >= maxBackups
+		if tooOld || tooMany {
+			_ = removeLogFileFn(path)
+		}
+	}
+}
+
+// backupTimestamp extracts the nanosecond timestamp rotate() embeds in a
+// backup's file name (ignoring a trailing ".gz" from Compress), reporting
+// false for anything that isn't a rotated backup.
+func backupTimestamp(path string) (int64, bool) {
+	base := strings.TrimSuffix(filepath.Base(path), ".gz")
+	pid, ok := parsePIDFromLog(base)
+	if !ok {
+		return 0, false
+	}
+	rest := strings.TrimSuffix(base, ".log")
+	rest = strings.TrimPrefix(rest, fmt.Sprintf("%s-%d-", WrapperName, pid))
+	if !backupTimestampRe.MatchString(rest) {
+		return 0, false
+	}
+	ts, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return ts, true
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerRotatesOnSize(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.SetRotationPolicy(RotationPolicy{MaxSize: 64})
+
+	for i := 0; i < 20; i++ {
+		logger.Info(fmt.Sprintf("message number %03d padded to push past the size threshold", i))
+	}
+	logger.Flush()
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+
+	var backups, active int
+	for _, e := range entries {
+		if _, isBackup := backupTimestamp(filepath.Join(tempDir, e.Name())); isBackup {
+			backups++
+			continue
+		}
+		if e.Name() == filepath.Base(logger.Path()) {
+			active++
+		}
+	}
+
+	if backups == 0 {
+		t.Fatalf("expected at least one rotated backup, found none among %v", entries)
+	}
+	if active != 1 {
+		t.Fatalf("expected exactly one active log file to remain, got %d", active)
+	}
+	if _, err := os.Stat(logger.Path()); err != nil {
+		t.Fatalf("expected active log file to exist: %v", err)
+	}
+}
+
+func TestLoggerRotationCompresses(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.SetRotationPolicy(RotationPolicy{MaxSize: 32, Compress: true})
+	for i := 0; i < 10; i++ {
+		logger.Info(fmt.Sprintf("entry %03d with enough text to exceed the tiny max size", i))
+	}
+	logger.Flush()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		entries, _ := os.ReadDir(tempDir)
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".log.gz") {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected a compressed backup (*.log.gz) to appear in %s", tempDir)
+}
+
+func TestCleanupOldLogsPrunesBackupsPastMaxBackups(t *testing.T) {
+	tempDir := setTempDirEnv(t, t.TempDir())
+
+	pid := 7001
+	base := time.Now().UnixNano()
+	var paths []string
+	for i := 0; i < defaultMaxBackupsPerPID+3; i++ {
+		name := fmt.Sprintf("%s-%d-%d.log", WrapperName, pid, base-int64(i)*int64(time.Second))
+		paths = append(paths, createTempLog(t, tempDir, name))
+	}
+
+	stubProcessRunning(t, func(int) bool {
+		t.Fatalf("process liveness should not be checked for rotated backups")
+		return false
+	})
+	stubProcessStartTime(t, func(int) time.Time { return time.Time{} })
+
+	stats, err := cleanupOldLogs()
+	if err != nil {
+		t.Fatalf("cleanupOldLogs() unexpected error: %v", err)
+	}
+
+	want := CleanupStats{Scanned: len(paths), Kept: defaultMaxBackupsPerPID, Deleted: 3}
+	if !compareCleanupStats(stats, want) {
+		t.Fatalf("cleanup stats mismatch: got %+v, want %+v", stats, want)
+	}
+
+	// The newest defaultMaxBackupsPerPID backups (smallest index) must survive.
+	for i := 0; i < defaultMaxBackupsPerPID; i++ {
+		if _, err := os.Stat(paths[i]); err != nil {
+			t.Fatalf("expected recent backup %s to remain, err=%v", paths[i], err)
+		}
+	}
+	for i := defaultMaxBackupsPerPID; i < len(paths); i++ {
+		if _, err := os.Stat(paths[i]); !os.IsNotExist(err) {
+			t.Fatalf("expected old backup %s to be pruned, err=%v", paths[i], err)
+		}
+	}
+}
+
+func TestCleanupOldLogsPrunesBackupsPastMaxAge(t *testing.T) {
+	tempDir := setTempDirEnv(t, t.TempDir())
+
+	pid := 7002
+	fresh := time.Now().UnixNano()
+	stale := time.Now().Add(-(defaultBackupMaxAge + time.Hour)).UnixNano()
+
+	freshPath := createTempLog(t, tempDir, fmt.Sprintf("%s-%d-%d.log", WrapperName, pid, fresh))
+	stalePath := createTempLog(t, tempDir, fmt.Sprintf("%s-%d-%d.log", WrapperName, pid, stale))
+
+	stubProcessRunning(t, func(int) bool {
+		t.Fatalf("process liveness should not be checked for rotated backups")
+		return false
+	})
+	stubProcessStartTime(t, func(int) time.Time { return time.Time{} })
+
+	stats, err := cleanupOldLogs()
+	if err != nil {
+		t.Fatalf("cleanupOldLogs() unexpected error: %v", err)
+	}
+
+	want := CleanupStats{Scanned: 2, Kept: 1, Deleted: 1}
+	if !compareCleanupStats(stats, want) {
+		t.Fatalf("cleanup stats mismatch: got %+v, want %+v", stats, want)
+	}
+	if _, err := os.Stat(freshPath); err != nil {
+		t.Fatalf("expected fresh backup to remain, err=%v", err)
+	}
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Fatalf("expected stale backup to be pruned, err=%v", err)
+	}
+}
+
+func TestBackupTimestampDistinguishesActiveFromBackup(t *testing.T) {
+	if _, ok := backupTimestamp("/tmp/codeagent-wrapper-123.log"); ok {
+		t.Fatalf("plain active log file should not be classified as a backup")
+	}
+	if _, ok := backupTimestamp("/tmp/codeagent-wrapper-123-task1.log"); ok {
+		t.Fatalf("suffix-named log file should not be classified as a backup")
+	}
+	ts, ok := backupTimestamp("/tmp/codeagent-wrapper-123-1700000000000000000.log")
+	if !ok || ts != 1700000000000000000 {
+		t.Fatalf("expected rotated backup to be recognized with its timestamp, got ts=%d ok=%v", ts, ok)
+	}
+	if ts, ok := backupTimestamp("/tmp/codeagent-wrapper-123-1700000000000000000.log.gz"); !ok || ts != 1700000000000000000 {
+		t.Fatalf("expected compressed rotated backup to be recognized, got ts=%d ok=%v", ts, ok)
+	}
+}
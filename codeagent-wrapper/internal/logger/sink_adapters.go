@@ -0,0 +1,31 @@
+package logger
+
+// hookSink adapts an existing Hook (SyslogHook, JournaldHook, ...) to the
+// Sink interface, so a Logger's per-instance fanout can reuse those
+// wire-protocol implementations instead of duplicating them.
+type hookSink struct {
+	hook Hook
+}
+
+// SinkFromHook wraps h as a Sink for use with WithSink. Flush and Close are
+// no-ops unless h also implements them (SyslogHook and JournaldHook both
+// implement Close; neither buffers, so neither needs Flush).
+func SinkFromHook(h Hook) Sink {
+	return hookSink{hook: h}
+}
+
+func (s hookSink) Write(entry Entry) error { return s.hook.Fire(entry) }
+
+func (s hookSink) Flush() error {
+	if f, ok := s.hook.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (s hookSink) Close() error {
+	if c, ok := s.hook.(interface{ Close() error }); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// Sink receives every entry a Logger writes to its own PID file, in
+// addition to that file. Unlike Hook (package-level, fired by the
+// logDebug/Info/Warn/Error helpers), a Sink is attached to one Logger
+// instance via WithSink and sees exactly what that instance writes.
+type Sink interface {
+	Write(entry Entry) error
+	Flush() error
+	Close() error
+}
+
+// LoggerOption configures a Logger at construction time (NewLogger,
+// NewLoggerWithSuffix, NewLoggerWithFormat).
+type LoggerOption func(*Logger)
+
+// WithSink registers sinks a Logger fans every entry out to, each through
+// its own bounded queue so a slow or unreachable sink (syslog, journald, an
+// OTLP collector over the network) can't block local file writes or the
+// other sinks. A queue that fills drops its oldest entry to make room,
+// counting the drop in Logger.Stats().
+func WithSink(sinks ...Sink) LoggerOption {
+	return func(l *Logger) {
+		for _, s := range sinks {
+			if s == nil {
+				continue
+			}
+			l.sinks = append(l.sinks, newSinkWorker(s))
+		}
+	}
+}
+
+// sinkQueueCapacity bounds each sink's pending-entry queue.
+const sinkQueueCapacity = 256
+
+// sinkWorker runs one Sink on its own goroutine, fed by a bounded,
+// drop-oldest queue so enqueue never blocks the Logger's worker goroutine.
+type sinkWorker struct {
+	sink    Sink
+	ch      chan Entry
+	done    chan struct{}
+	dropped int64 // atomic
+}
+
+func newSinkWorker(s Sink) *sinkWorker {
+	sw := &sinkWorker{sink: s, ch: make(chan Entry, sinkQueueCapacity), done: make(chan struct{})}
+	go sw.run()
+	return sw
+}
+
+func (sw *sinkWorker) run() {
+	defer close(sw.done)
+	for e := range sw.ch {
+		sw.deliver(e)
+	}
+}
+
+// deliver isolates one sink's failure (including a panic), mirroring
+// fireHookSafely's isolation for package-level Hooks.
+func (sw *sinkWorker) deliver(e Entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "codeagent-wrapper: log sink panicked: %v\n", r)
+		}
+	}()
+	if err := sw.sink.Write(e); err != nil {
+		fmt.Fprintf(os.Stderr, "codeagent-wrapper: log sink failed: %v\n", err)
+	}
+}
+
+// enqueue never blocks: if the queue is full it drops the oldest pending
+// entry to make room for e, counting the drop.
+func (sw *sinkWorker) enqueue(e Entry) {
+	select {
+	case sw.ch <- e:
+		return
+	default:
+	}
+
+	select {
+	case <-sw.ch:
+		atomic.AddInt64(&sw.dropped, 1)
+	default:
+	}
+
+	select {
+	case sw.ch <- e:
+	default:
+		atomic.AddInt64(&sw.dropped, 1)
+	}
+}
+
+func (sw *sinkWorker) flush() error { return sw.sink.Flush() }
+
+func (sw *sinkWorker) close() error {
+	close(sw.ch)
+	<-sw.done
+	return sw.sink.Close()
+}
+
+// SinkStats reports how many entries a sink has dropped due to a full
+// queue.
+type SinkStats struct {
+	Dropped int64
+}
+
+// Stats returns one SinkStats per sink registered via WithSink, in
+// registration order. It returns nil for a nil/zero-value Logger or one
+// with no sinks.
+func (l *Logger) Stats() []SinkStats {
+	if l == nil || len(l.sinks) == 0 {
+		return nil
+	}
+	stats := make([]SinkStats, len(l.sinks))
+	for i, sw := range l.sinks {
+		stats[i] = SinkStats{Dropped: atomic.LoadInt64(&sw.dropped)}
+	}
+	return stats
+}
+
+// levelFromString maps the level strings writeEntry/formatLine use
+// ("DEBUG"/"INFO"/"WARN"/"ERROR") to the Level enum Sinks and Hooks share.
+func levelFromString(s string) Level {
+	switch s {
+	case "DEBUG":
+		return LevelDebug
+	case "WARN":
+		return LevelWarn
+	case "ERROR":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
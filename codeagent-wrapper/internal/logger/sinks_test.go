@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSink struct {
+	mu      sync.Mutex
+	entries []Entry
+	failErr error
+	closed  bool
+}
+
+func (s *fakeSink) Write(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failErr != nil {
+		return s.failErr
+	}
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *fakeSink) Flush() error { return nil }
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) snapshot() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Entry, len(s.entries))
+	copy(out, s.entries)
+	return out
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %s", timeout)
+}
+
+func TestLoggerFansOutToSinks(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	sink := &fakeSink{}
+	logger, err := NewLogger(WithSink(sink))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	logger.Warn("careful")
+	logger.Flush()
+
+	waitFor(t, time.Second, func() bool { return len(sink.snapshot()) == 2 })
+
+	entries := sink.snapshot()
+	if entries[0].Message != "hello" || entries[0].Level != LevelInfo {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Message != "careful" || entries[1].Level != LevelWarn {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestLoggerSinkFailureIsolatesOtherSinks(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	failing := &fakeSink{failErr: errors.New("boom")}
+	healthy := &fakeSink{}
+	logger, err := NewLogger(WithSink(failing, healthy))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	logger.Flush()
+
+	waitFor(t, time.Second, func() bool { return len(healthy.snapshot()) == 1 })
+
+	if len(healthy.snapshot()) != 1 {
+		t.Fatalf("expected the healthy sink to still receive the entry despite the failing sink")
+	}
+}
+
+func TestLoggerSinkQueueDropsOldestWhenFull(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	block := make(chan struct{})
+	blocking := &blockingSink{block: block}
+	logger, err := NewLogger(WithSink(blocking))
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer func() {
+		close(block)
+		logger.Close()
+	}()
+
+	for i := 0; i < sinkQueueCapacity+10; i++ {
+		logger.Info("filler")
+	}
+	logger.Flush()
+
+	stats := logger.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("expected one sink's stats, got %d", len(stats))
+	}
+	if stats[0].Dropped == 0 {
+		t.Fatalf("expected some entries to be dropped once the sink's queue filled, got 0")
+	}
+}
+
+// blockingSink never returns from Write until its block channel closes, so
+// its queue fills and starts dropping entries while the Logger's own file
+// writes keep proceeding unimpeded (exercised alongside
+// TestLoggerConcurrentWritesSafe's concurrent-writer guarantee).
+type blockingSink struct {
+	block chan struct{}
+	once  sync.Once
+}
+
+func (s *blockingSink) Write(entry Entry) error {
+	s.once.Do(func() { <-s.block })
+	return nil
+}
+
+func (s *blockingSink) Flush() error { return nil }
+func (s *blockingSink) Close() error { return nil }
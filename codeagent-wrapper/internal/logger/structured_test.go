@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+func TestLoggerWithFormatJSONWritesOneObjectPerLine(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLoggerWithFormat(FormatJSON)
+	if err != nil {
+		t.Fatalf("NewLoggerWithFormat() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.InfoFields("request handled", F("path", "/v1/run"), F("status", 200))
+	logger.Flush()
+
+	data, err := os.ReadFile(logger.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected exactly one line, got %d: %q", len(lines), data)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+
+	if got["msg"] != "request handled" {
+		t.Errorf("msg = %v, want %q", got["msg"], "request handled")
+	}
+	if got["level"] != "INFO" {
+		t.Errorf("level = %v, want %q", got["level"], "INFO")
+	}
+	if _, ok := got["ts"]; !ok {
+		t.Error("expected a ts field")
+	}
+	if pid, ok := got["pid"].(float64); !ok || int(pid) != os.Getpid() {
+		t.Errorf("pid = %v, want %d", got["pid"], os.Getpid())
+	}
+	if got["path"] != "/v1/run" {
+		t.Errorf("path field = %v, want %q", got["path"], "/v1/run")
+	}
+	if status, ok := got["status"].(float64); !ok || int(status) != 200 {
+		t.Errorf("status field = %v, want 200", got["status"])
+	}
+}
+
+func TestLoggerWithFormatTextIgnoresFields(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.InfoFields("plain message", F("ignored", true))
+	logger.Flush()
+
+	data, err := os.ReadFile(logger.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), "INFO plain message") {
+		t.Fatalf("expected text-formatted line, got %q", data)
+	}
+	if strings.Contains(string(data), "ignored") {
+		t.Fatalf("expected fields to be ignored in FormatText, got %q", data)
+	}
+}
+
+func TestLoggerJSONFormatPreservesErrorCacheSemantics(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLoggerWithFormat(FormatJSON)
+	if err != nil {
+		t.Fatalf("NewLoggerWithFormat() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.ErrorFields("disk full", F("bytes_free", 0))
+	logger.WarnFields("retrying", F("attempt", 2))
+	logger.Flush()
+
+	got := logger.ExtractRecentErrors(10)
+	want := []string{"disk full", "retrying"}
+	if len(got) != len(want) {
+		t.Fatalf("ExtractRecentErrors() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTypedFieldHelpersEncodeLikeF(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+
+	logger, err := NewLoggerWithFormat(FormatJSON)
+	if err != nil {
+		t.Fatalf("NewLoggerWithFormat() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.InfoFields("typed fields",
+		String("path", "/v1/run"),
+		Int("status", 200),
+		Duration("took", 150*time.Millisecond),
+		Err(errors.New("boom")),
+	)
+	logger.Flush()
+
+	data, err := os.ReadFile(logger.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimRight(string(data), "\n")), &got); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+
+	if got["path"] != "/v1/run" {
+		t.Errorf("path = %v, want %q", got["path"], "/v1/run")
+	}
+	if status, ok := got["status"].(float64); !ok || int(status) != 200 {
+		t.Errorf("status = %v, want 200", got["status"])
+	}
+	if got["error"] != "boom" {
+		t.Errorf("error = %v, want %q", got["error"], "boom")
+	}
+}
+
+func TestErrFieldWithNilError(t *testing.T) {
+	f := Err(nil)
+	if f.Key != "error" || f.Value != nil {
+		t.Fatalf("Err(nil) = %+v, want Key=error Value=nil", f)
+	}
+}
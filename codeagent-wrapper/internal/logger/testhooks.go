@@ -65,3 +65,36 @@ func SetEvalSymlinksFn(fn func(string) (string, error)) (restore func()) {
 	}
 	return func() { evalSymlinksFn = prev }
 }
+
+func SetReadLogFileFn(fn func(string) ([]byte, error)) (restore func()) {
+	prev := readLogFileFn
+	if fn != nil {
+		readLogFileFn = fn
+	} else {
+		readLogFileFn = os.ReadFile
+	}
+	return func() { readLogFileFn = prev }
+}
+
+func SetFingerprintFn(fn func(int) (string, error)) (restore func()) {
+	prev := fingerprintFn
+	if fn != nil {
+		fingerprintFn = fn
+	} else {
+		fingerprintFn = func(pid int) (string, error) { return defaultProcessIdentity.Fingerprint(pid) }
+	}
+	return func() { fingerprintFn = prev }
+}
+
+// SetCleanupLockTimeout overrides how long cleanupOldLogs waits to acquire
+// the cross-process cleanup lock before giving up with
+// ErrCleanupInProgress. d <= 0 restores the default.
+func SetCleanupLockTimeout(d time.Duration) (restore func()) {
+	prev := cleanupLockTimeout
+	if d > 0 {
+		cleanupLockTimeout = d
+	} else {
+		cleanupLockTimeout = defaultCleanupLockTimeout
+	}
+	return func() { cleanupLockTimeout = prev }
+}
@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	opencodeParserName = "opencode"
+	geminiParserName   = "gemini"
+	claudeParserName   = "claude"
+	codexParserName    = "codex"
+)
+
+type opencodeStreamParser struct{}
+
+func (opencodeStreamParser) Detect(rawLine []byte) bool {
+	var probe struct {
+		SessionID string          `json:"sessionID"`
+		Part      json.RawMessage `json:"part"`
+	}
+	if json.Unmarshal(rawLine, &probe) != nil {
+		return false
+	}
+	return probe.SessionID != "" && len(probe.Part) > 0
+}
+
+func (opencodeStreamParser) HandleEvent(ctx *ParseCtx) error {
+	event := ctx.Event
+	if ctx.ThreadID == "" {
+		ctx.ThreadID = event.OpencodeSessionID
+	}
+
+	var part OpencodePart
+	if err := json.Unmarshal(event.Part, &part); err != nil {
+		ctx.Warn(fmt.Sprintf("Failed to parse opencode part: %s", err.Error()))
+		return nil
+	}
+
+	if part.SessionID != "" && ctx.ThreadID == "" {
+		ctx.ThreadID = part.SessionID
+	}
+
+	ctx.Info(fmt.Sprintf("Parsed Opencode event #%d type=%s part_type=%s", ctx.Index, event.Type, part.Type))
+
+	if event.Type == "text" && part.Text != "" {
+		ctx.Buffer(opencodeParserName).WriteString(part.Text)
+		ctx.NotifyMessage()
+	}
+
+	if part.Type == "step-finish" && part.Reason == "stop" {
+		ctx.NotifyComplete()
+	}
+	return nil
+}
+
+func (opencodeStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(opencodeParserName).String()
+}
+
+type codexStreamParser struct{}
+
+func (codexStreamParser) Detect(rawLine []byte) bool {
+	var probe struct {
+		Type     string          `json:"type"`
+		ThreadID string          `json:"thread_id"`
+		Item     json.RawMessage `json:"item"`
+	}
+	if json.Unmarshal(rawLine, &probe) != nil {
+		return false
+	}
+	if probe.ThreadID != "" {
+		return true
+	}
+	if len(probe.Item) > 0 {
+		var itemHeader struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(probe.Item, &itemHeader) == nil && itemHeader.Type != "" {
+			return true
+		}
+	}
+	return probe.Type == "turn.started" || probe.Type == "turn.completed"
+}
+
+func (codexStreamParser) HandleEvent(ctx *ParseCtx) error {
+	event := ctx.Event
+
+	var details []string
+	if event.ThreadID != "" {
+		details = append(details, fmt.Sprintf("thread_id=%s", event.ThreadID))
+	}
+	if len(details) > 0 {
+		ctx.Info(fmt.Sprintf("Parsed event #%d type=%s (%s)", ctx.Index, event.Type, strings.Join(details, ", ")))
+	} else {
+		ctx.Info(fmt.Sprintf("Parsed event #%d type=%s", ctx.Index, event.Type))
+	}
+
+	switch event.Type {
+	case "thread.started":
+		ctx.ThreadID = event.ThreadID
+		ctx.Info(fmt.Sprintf("thread.started event thread_id=%s", ctx.ThreadID))
+
+	case "thread.completed":
+		if event.ThreadID != "" && ctx.ThreadID == "" {
+			ctx.ThreadID = event.ThreadID
+		}
+		ctx.Info(fmt.Sprintf("thread.completed event thread_id=%s", event.ThreadID))
+		ctx.NotifyComplete()
+
+	case "turn.completed":
+		ctx.Info("turn.completed event")
+		ctx.NotifyComplete()
+
+	case "item.completed":
+		var itemType string
+		if len(event.Item) > 0 {
+			var itemHeader struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(event.Item, &itemHeader); err == nil {
+				itemType = itemHeader.Type
+			}
+		}
+
+		if itemType == "agent_message" && len(event.Item) > 0 {
+			var item ItemContent
+			if err := json.Unmarshal(event.Item, &item); err == nil {
+				normalized := NormalizeText(item.Text)
+				ctx.Info(fmt.Sprintf("item.completed event item_type=%s message_len=%d", itemType, len(normalized)))
+				if normalized != "" {
+					buf := ctx.Buffer(codexParserName)
+					buf.Reset()
+					buf.WriteString(normalized)
+					ctx.NotifyMessage()
+				}
+			} else {
+				ctx.Warn(fmt.Sprintf("Failed to parse item content: %s", err.Error()))
+			}
+		} else {
+			ctx.Info(fmt.Sprintf("item.completed event item_type=%s", itemType))
+		}
+	}
+	return nil
+}
+
+func (codexStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(codexParserName).String()
+}
+
+type claudeStreamParser struct{}
+
+func (claudeStreamParser) Detect(rawLine []byte) bool {
+	var probe struct {
+		Type      string `json:"type"`
+		Subtype   string `json:"subtype"`
+		SessionID string `json:"session_id"`
+		Result    string `json:"result"`
+		Status    string `json:"status"`
+	}
+	if json.Unmarshal(rawLine, &probe) != nil {
+		return false
+	}
+	if probe.Subtype != "" || probe.Result != "" {
+		return true
+	}
+	return probe.Type == "result" && probe.SessionID != "" && probe.Status == ""
+}
+
+func (claudeStreamParser) HandleEvent(ctx *ParseCtx) error {
+	event := ctx.Event
+	if event.SessionID != "" && ctx.ThreadID == "" {
+		ctx.ThreadID = event.SessionID
+	}
+
+	ctx.Info(fmt.Sprintf("Parsed Claude event #%d type=%s subtype=%s result_len=%d", ctx.Index, event.Type, event.Subtype, len(event.Result)))
+
+	if event.Result != "" {
+		buf := ctx.Buffer(claudeParserName)
+		buf.Reset()
+		buf.WriteString(event.Result)
+		ctx.NotifyMessage()
+	}
+
+	if event.Type == "result" {
+		ctx.NotifyComplete()
+	}
+	return nil
+}
+
+func (claudeStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(claudeParserName).String()
+}
+
+type geminiStreamParser struct{}
+
+func (geminiStreamParser) Detect(rawLine []byte) bool {
+	var probe struct {
+		Type      string `json:"type"`
+		SessionID string `json:"session_id"`
+		Role      string `json:"role"`
+		Delta     *bool  `json:"delta"`
+		Status    string `json:"status"`
+	}
+	if json.Unmarshal(rawLine, &probe) != nil {
+		return false
+	}
+	if probe.Type == "init" && probe.SessionID != "" {
+		return true
+	}
+	return probe.Role != "" || probe.Delta != nil || probe.Status != ""
+}
+
+func (geminiStreamParser) HandleEvent(ctx *ParseCtx) error {
+	event := ctx.Event
+	if event.SessionID != "" && ctx.ThreadID == "" {
+		ctx.ThreadID = event.SessionID
+	}
+
+	if event.Content != "" {
+		ctx.Buffer(geminiParserName).WriteString(event.Content)
+	}
+
+	if event.Status != "" {
+		ctx.NotifyMessage()
+		if event.Type == "result" && (event.Status == "success" || event.Status == "error" || event.Status == "complete" || event.Status == "failed") {
+			ctx.NotifyComplete()
+		}
+	}
+
+	delta := false
+	if event.Delta != nil {
+		delta = *event.Delta
+	}
+
+	ctx.Info(fmt.Sprintf("Parsed Gemini event #%d type=%s role=%s delta=%t status=%s content_len=%d", ctx.Index, event.Type, event.Role, delta, event.Status, len(event.Content)))
+	return nil
+}
+
+func (geminiStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(geminiParserName).String()
+}
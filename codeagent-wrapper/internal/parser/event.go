@@ -57,6 +57,26 @@ type UnifiedEvent struct {
 	// Opencode-specific fields (camelCase sessionID)
 	OpencodeSessionID string          `json:"sessionID,omitempty"`
 	Part              json.RawMessage `json:"part,omitempty"`
+
+	// OpenAI chat.completion.chunk-specific fields (SSE "data: " payloads;
+	// see stripSSEDataPrefix).
+	Object  string               `json:"object,omitempty"`
+	Choices []UnifiedEventChoice `json:"choices,omitempty"`
+
+	// Generic NDJSON passthrough fields, for backends whose events carry
+	// plain text under one of these common top-level keys instead of any
+	// of the backend-specific shapes above.
+	Text    string `json:"text,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// UnifiedEventChoice is one entry of an OpenAI chat.completion.chunk's
+// "choices" array.
+type UnifiedEventChoice struct {
+	Delta struct {
+		Content string `json:"content,omitempty"`
+	} `json:"delta,omitempty"`
+	FinishReason *string `json:"finish_reason,omitempty"`
 }
 
 // OpencodePart represents the part field in opencode events.
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	openAIParserName        = "openai"
+	genericNDJSONParserName = "ndjson"
+)
+
+var sseDataPrefix = []byte("data:")
+
+// stripSSEDataPrefix strips OpenAI-style SSE framing ("data: {...}") off
+// line so the core per-line loop in parseJSONStream can hand every format -
+// bare JSON lines or SSE-framed ones - to the same json.Unmarshal call. It
+// reports skip=true for lines that carry no JSON payload at all: the
+// terminating "data: [DONE]" sentinel, a "data:" line with an empty
+// payload, and SSE comment/keepalive lines starting with ":" (some SSE
+// servers send a bare ":" line to keep the connection alive). Any other
+// line without the "data:" prefix is returned unchanged, so non-SSE
+// backends are unaffected.
+func stripSSEDataPrefix(line []byte) (out []byte, skip bool) {
+	if bytes.HasPrefix(line, []byte(":")) {
+		return nil, true
+	}
+	if !bytes.HasPrefix(line, sseDataPrefix) {
+		return line, false
+	}
+	payload := bytes.TrimSpace(line[len(sseDataPrefix):])
+	if len(payload) == 0 || bytes.Equal(payload, []byte("[DONE]")) {
+		return nil, true
+	}
+	return payload, false
+}
+
+// openAIStreamParser handles OpenAI-style chat.completion.chunk SSE
+// payloads. Their "data: " framing isn't bare JSON, so stripSSEDataPrefix
+// in parseJSONStream strips it (and drops the terminating "data: [DONE]")
+// before the shared UnifiedEvent unmarshal runs; by the time Detect sees
+// rawLine it's already the chunk's plain JSON body.
+type openAIStreamParser struct{}
+
+func (openAIStreamParser) Detect(rawLine []byte) bool {
+	var probe struct {
+		Object string `json:"object"`
+	}
+	if json.Unmarshal(rawLine, &probe) != nil {
+		return false
+	}
+	return probe.Object == "chat.completion.chunk"
+}
+
+func (openAIStreamParser) HandleEvent(ctx *ParseCtx) error {
+	event := ctx.Event
+
+	for _, choice := range event.Choices {
+		if choice.Delta.Content != "" {
+			ctx.Buffer(openAIParserName).WriteString(choice.Delta.Content)
+			ctx.NotifyMessage()
+		}
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			ctx.NotifyComplete()
+		}
+	}
+
+	ctx.Info(fmt.Sprintf("Parsed OpenAI chat.completion.chunk #%d", ctx.Index))
+	return nil
+}
+
+func (openAIStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(openAIParserName).String()
+}
+
+// genericNDJSONStreamParser is a catch-all passthrough for backends that
+// emit plain NDJSON with message text under one of a few common top-level
+// keys, without any of the richer shape the backend-specific parsers above
+// look for. It only claims a line when one of those keys is actually
+// present and non-empty, so it stays a narrow addition to the default
+// detection chain rather than a silent catch-all; forcing it via
+// --stream-format (see SetForcedStreamParser) bypasses Detect entirely for
+// callers that know every line in the stream is this shape.
+type genericNDJSONStreamParser struct{}
+
+func (genericNDJSONStreamParser) Detect(rawLine []byte) bool {
+	var probe struct {
+		Content string `json:"content"`
+		Text    string `json:"text"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(rawLine, &probe) != nil {
+		return false
+	}
+	return probe.Content != "" || probe.Text != "" || probe.Message != ""
+}
+
+func (genericNDJSONStreamParser) HandleEvent(ctx *ParseCtx) error {
+	event := ctx.Event
+
+	text := event.Content
+	if text == "" {
+		text = event.Text
+	}
+	if text == "" {
+		text = event.Message
+	}
+	if text != "" {
+		ctx.Buffer(genericNDJSONParserName).WriteString(text)
+		ctx.NotifyMessage()
+	}
+
+	ctx.Info(fmt.Sprintf("Parsed generic NDJSON event #%d", ctx.Index))
+	return nil
+}
+
+func (genericNDJSONStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(genericNDJSONParserName).String()
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripSSEDataPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantOut  string
+		wantSkip bool
+	}{
+		{"bare JSON unaffected", `{"object":"x"}`, `{"object":"x"}`, false},
+		{"data-prefixed JSON stripped", `data: {"object":"x"}`, `{"object":"x"}`, false},
+		{"done sentinel skipped", `data: [DONE]`, "", true},
+		{"blank keepalive skipped", `data:`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, skip := stripSSEDataPrefix([]byte(tt.line))
+			if skip != tt.wantSkip {
+				t.Fatalf("skip = %v, want %v", skip, tt.wantSkip)
+			}
+			if !skip && string(out) != tt.wantOut {
+				t.Fatalf("out = %q, want %q", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestParseJSONStreamInternalOpenAISSE(t *testing.T) {
+	input := "data: " + `{"object":"chat.completion.chunk","choices":[{"delta":{"content":"hel"}}]}` + "\n" +
+		"data: " + `{"object":"chat.completion.chunk","choices":[{"delta":{"content":"lo"},"finish_reason":"stop"}]}` + "\n" +
+		"data: [DONE]\n"
+
+	msg, _ := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "hello" {
+		t.Fatalf("expected accumulated OpenAI delta content %q, got %q", "hello", msg)
+	}
+}
+
+func TestGenericNDJSONStreamParserDoesNotShadowUnknownEvents(t *testing.T) {
+	// Matches TestParseJSONStreamInternalUnknownEventFallback's input: no
+	// content/text/message key, so the default chain must still ignore it.
+	input := "{\"type\":\"assistant\",\"foo\":\"bar\"}\n"
+	msg, threadID := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "" || threadID != "" {
+		t.Fatalf("expected generic NDJSON parser not to claim an unrelated event, got message=%q threadID=%q", msg, threadID)
+	}
+}
+
+func TestParseJSONStreamInternalGenericNDJSONForced(t *testing.T) {
+	restore := SetForcedStreamParser("ndjson")
+	defer restore()
+
+	input := `{"text":"hel"}` + "\n" + `{"text":"lo"}` + "\n"
+	msg, _ := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "hello" {
+		t.Fatalf("expected forced ndjson parser to accumulate text fields, got %q", msg)
+	}
+}
+
+func TestSetForcedStreamParserRestoresPreviousSelection(t *testing.T) {
+	outer := SetForcedStreamParser("openai")
+	inner := SetForcedStreamParser("ndjson")
+	inner()
+
+	if name, _ := forcedStreamParser.Load().(string); name != "openai" {
+		t.Fatalf("expected restore to bring back %q, got %q", "openai", name)
+	}
+	outer()
+
+	if name, _ := forcedStreamParser.Load().(string); name != "" {
+		t.Fatalf("expected restore to clear forced selection, got %q", name)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"sync"
 
+	metrics "codeagent-wrapper/internal/executor/metrics"
+
 	"github.com/goccy/go-json"
 )
 
@@ -35,6 +37,25 @@ var lineScratchPool = sync.Pool{
 }
 
 func ParseJSONStreamInternal(r io.Reader, warnFn func(string), infoFn func(string), onMessage func(), onComplete func()) (message, threadID string) {
+	if useTokenizedStreamParser.Load() {
+		return ParseJSONStreamTokenized(r, warnFn, infoFn, onMessage, onComplete)
+	}
+	return parseJSONStream(r, "", warnFn, infoFn, onMessage, onComplete)
+}
+
+// ParseJSONStreamWithMetrics runs the line-delimited parser that
+// ParseJSONStreamInternal uses by default, labeling the
+// codeagent_stream_events_total and codeagent_overlong_lines_total samples
+// it records on the process-wide metrics.Active() sink with backendName
+// (codeagent_json_line_bytes carries no backend label).
+// ParseJSONStreamInternal's line-delimited path records the same metrics
+// with an empty backend label; neither records codeagent_tasks_total.
+func ParseJSONStreamWithMetrics(r io.Reader, backendName string, warnFn func(string), infoFn func(string), onMessage func(), onComplete func()) (message, threadID string) {
+	return parseJSONStream(r, backendName, warnFn, infoFn, onMessage, onComplete)
+}
+
+func parseJSONStream(r io.Reader, backendName string, warnFn func(string), infoFn func(string), onMessage func(), onComplete func()) (message, threadID string) {
+	sink := metrics.Active()
 	reader := bufio.NewReaderSize(r, jsonLineReaderSize)
 	scratch := lineScratchPool.Get().(*lineScratch)
 	if scratch.buf == nil {
@@ -68,26 +89,15 @@ func ParseJSONStreamInternal(r io.Reader, warnFn func(string), infoFn func(strin
 		infoFn = func(string) {}
 	}
 
-	notifyMessage := func() {
-		if onMessage != nil {
-			onMessage()
-		}
-	}
-
-	notifyComplete := func() {
-		if onComplete != nil {
-			onComplete()
-		}
-	}
-
 	totalEvents := 0
 
-	var (
-		codexMessage    string
-		claudeMessage   string
-		geminiBuffer    strings.Builder
-		opencodeMessage strings.Builder
-	)
+	ctx := &ParseCtx{
+		warnFn:     warnFn,
+		infoFn:     infoFn,
+		onMessage:  onMessage,
+		onComplete: onComplete,
+	}
+	parsers := selectedStreamParsers()
 
 	for {
 		line, tooLong, err := readLineWithLimit(reader, jsonLineMaxBytes, jsonLinePreviewBytes, scratch)
@@ -103,9 +113,15 @@ func ParseJSONStreamInternal(r io.Reader, warnFn func(string), infoFn func(strin
 		if len(line) == 0 {
 			continue
 		}
+		line, skip := stripSSEDataPrefix(line)
+		if skip {
+			continue
+		}
 		totalEvents++
+		sink.ObserveJSONLineBytes(len(line))
 
 		if tooLong {
+			sink.IncOverlongLine(backendName)
 			warnFn(fmt.Sprintf("Skipped overlong JSON line (> %d bytes): %s", jsonLineMaxBytes, TruncateBytes(line, 100)))
 			continue
 		}
@@ -116,177 +132,35 @@ func ParseJSONStreamInternal(r io.Reader, warnFn func(string), infoFn func(strin
 			warnFn(fmt.Sprintf("Failed to parse event: %s", TruncateBytes(line, 100)))
 			continue
 		}
+		sink.IncStreamEvent(backendName, event.Type)
 
-		// Detect backend type by field presence
-		isCodex := event.ThreadID != ""
-		if !isCodex && len(event.Item) > 0 {
-			var itemHeader struct {
-				Type string `json:"type"`
-			}
-			if json.Unmarshal(event.Item, &itemHeader) == nil && itemHeader.Type != "" {
-				isCodex = true
-			}
-		}
-		// Codex-specific event types without thread_id or item
-		if !isCodex && (event.Type == "turn.started" || event.Type == "turn.completed") {
-			isCodex = true
-		}
-		isClaude := event.Subtype != "" || event.Result != ""
-		if !isClaude && event.Type == "result" && event.SessionID != "" && event.Status == "" {
-			isClaude = true
-		}
-		isGemini := (event.Type == "init" && event.SessionID != "") || event.Role != "" || event.Delta != nil || event.Status != ""
-		isOpencode := event.OpencodeSessionID != "" && len(event.Part) > 0
+		ctx.RawLine = line
+		ctx.Event = event
+		ctx.Index = totalEvents
 
-		// Handle Opencode events first (most specific detection)
-		if isOpencode {
-			if threadID == "" {
-				threadID = event.OpencodeSessionID
-			}
-
-			var part OpencodePart
-			if err := json.Unmarshal(event.Part, &part); err != nil {
-				warnFn(fmt.Sprintf("Failed to parse opencode part: %s", err.Error()))
+		handled := false
+		for _, rp := range parsers {
+			if !rp.parser.Detect(line) {
 				continue
 			}
-
-			// Extract sessionID from part if available
-			if part.SessionID != "" && threadID == "" {
-				threadID = part.SessionID
+			if err := rp.parser.HandleEvent(ctx); err != nil {
+				warnFn(fmt.Sprintf("stream parser %q: %s", rp.name, err.Error()))
 			}
-
-			infoFn(fmt.Sprintf("Parsed Opencode event #%d type=%s part_type=%s", totalEvents, event.Type, part.Type))
-
-			if event.Type == "text" && part.Text != "" {
-				opencodeMessage.WriteString(part.Text)
-				notifyMessage()
-			}
-
-			if part.Type == "step-finish" && part.Reason == "stop" {
-				notifyComplete()
-			}
-			continue
-		}
-
-		// Handle Codex events
-		if isCodex {
-			var details []string
-			if event.ThreadID != "" {
-				details = append(details, fmt.Sprintf("thread_id=%s", event.ThreadID))
-			}
-
-			if len(details) > 0 {
-				infoFn(fmt.Sprintf("Parsed event #%d type=%s (%s)", totalEvents, event.Type, strings.Join(details, ", ")))
-			} else {
-				infoFn(fmt.Sprintf("Parsed event #%d type=%s", totalEvents, event.Type))
-			}
-
-			switch event.Type {
-			case "thread.started":
-				threadID = event.ThreadID
-				infoFn(fmt.Sprintf("thread.started event thread_id=%s", threadID))
-
-			case "thread.completed":
-				if event.ThreadID != "" && threadID == "" {
-					threadID = event.ThreadID
-				}
-				infoFn(fmt.Sprintf("thread.completed event thread_id=%s", event.ThreadID))
-				notifyComplete()
-
-			case "turn.completed":
-				infoFn("turn.completed event")
-				notifyComplete()
-
-			case "item.completed":
-				var itemType string
-				if len(event.Item) > 0 {
-					var itemHeader struct {
-						Type string `json:"type"`
-					}
-					if err := json.Unmarshal(event.Item, &itemHeader); err == nil {
-						itemType = itemHeader.Type
-					}
-				}
-
-				if itemType == "agent_message" && len(event.Item) > 0 {
-					// Lazy parse: only parse item content when needed
-					var item ItemContent
-					if err := json.Unmarshal(event.Item, &item); err == nil {
-						normalized := NormalizeText(item.Text)
-						infoFn(fmt.Sprintf("item.completed event item_type=%s message_len=%d", itemType, len(normalized)))
-						if normalized != "" {
-							codexMessage = normalized
-							notifyMessage()
-						}
-					} else {
-						warnFn(fmt.Sprintf("Failed to parse item content: %s", err.Error()))
-					}
-				} else {
-					infoFn(fmt.Sprintf("item.completed event item_type=%s", itemType))
-				}
-			}
-			continue
-		}
-
-		// Handle Claude events
-		if isClaude {
-			if event.SessionID != "" && threadID == "" {
-				threadID = event.SessionID
-			}
-
-			infoFn(fmt.Sprintf("Parsed Claude event #%d type=%s subtype=%s result_len=%d", totalEvents, event.Type, event.Subtype, len(event.Result)))
-
-			if event.Result != "" {
-				claudeMessage = event.Result
-				notifyMessage()
-			}
-
-			if event.Type == "result" {
-				notifyComplete()
-			}
-			continue
+			handled = true
+			break
 		}
-
-		// Handle Gemini events
-		if isGemini {
-			if event.SessionID != "" && threadID == "" {
-				threadID = event.SessionID
-			}
-
-			if event.Content != "" {
-				geminiBuffer.WriteString(event.Content)
-			}
-
-			if event.Status != "" {
-				notifyMessage()
-
-				if event.Type == "result" && (event.Status == "success" || event.Status == "error" || event.Status == "complete" || event.Status == "failed") {
-					notifyComplete()
-				}
-			}
-
-			delta := false
-			if event.Delta != nil {
-				delta = *event.Delta
-			}
-
-			infoFn(fmt.Sprintf("Parsed Gemini event #%d type=%s role=%s delta=%t status=%s content_len=%d", totalEvents, event.Type, event.Role, delta, event.Status, len(event.Content)))
+		if !handled {
+			// Unknown event format from other backends (turn.started/assistant/user); ignore.
 			continue
 		}
-
-		// Unknown event format from other backends (turn.started/assistant/user); ignore.
-		continue
 	}
 
-	switch {
-	case opencodeMessage.Len() > 0:
-		message = opencodeMessage.String()
-	case geminiBuffer.Len() > 0:
-		message = geminiBuffer.String()
-	case claudeMessage != "":
-		message = claudeMessage
-	default:
-		message = codexMessage
+	threadID = ctx.ThreadID
+	for _, rp := range parsers {
+		if m := rp.parser.Finalize(ctx); m != "" {
+			message = m
+			break
+		}
 	}
 
 	infoFn(fmt.Sprintf("parseJSONStream completed: events=%d, message_len=%d, thread_id_found=%t", totalEvents, len(message), threadID != ""))
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type registeredStreamParser struct {
+	name   string
+	parser StreamParser
+}
+
+var (
+	streamParsersMu sync.RWMutex
+	streamParsers   []registeredStreamParser
+)
+
+// alwaysDetectStreamParser wraps a StreamParser so Detect unconditionally
+// claims every line, for use when a caller has forced a single named parser
+// via SetForcedStreamParser and already knows the stream's shape.
+type alwaysDetectStreamParser struct {
+	StreamParser
+}
+
+func (alwaysDetectStreamParser) Detect(rawLine []byte) bool { return true }
+
+var forcedStreamParser atomic.Value // holds string; empty means "no force"
+
+// SetForcedStreamParser pins ParseJSONStreamInternal to the single parser
+// registered under name, bypassing every parser's Detect (including the
+// forced one's own) for the lifetime of the process. Pass "" to return to
+// the normal priority-ordered detection chain. It returns a restore func so
+// callers (tests, or a CLI flag applied for one run) can put the previous
+// selection back. This exists so narrowly-scoped parsers like the generic
+// NDJSON passthrough - which deliberately don't claim every line by default,
+// to avoid becoming an unconditional catch-all - can still be used for a
+// backend whose format is known in advance.
+func SetForcedStreamParser(name string) (restore func()) {
+	prev, _ := forcedStreamParser.Swap(name).(string)
+	return func() { forcedStreamParser.Store(prev) }
+}
+
+// selectedStreamParsers returns the parser chain parseJSONStream should run:
+// the forced single parser (wrapped to always match), if one is set via
+// SetForcedStreamParser, or the full registered chain in priority order.
+func selectedStreamParsers() []registeredStreamParser {
+	if name, _ := forcedStreamParser.Load().(string); name != "" {
+		streamParsersMu.RLock()
+		defer streamParsersMu.RUnlock()
+		for _, rp := range streamParsers {
+			if rp.name == name {
+				return []registeredStreamParser{{name: rp.name, parser: alwaysDetectStreamParser{rp.parser}}}
+			}
+		}
+	}
+	return streamParserSnapshot()
+}
+
+// RegisterStreamParser registers p under name for use by
+// ParseJSONStreamInternal. Parsers are tried in registration order - Detect
+// is called on each until one returns true - so register higher-priority
+// or more-specific parsers first; this is also the order Finalize is
+// consulted when picking the resulting message. Re-registering an existing
+// name replaces its parser in place without changing its position. Third
+// parties can use this to plug in additional CLI agents without touching
+// the core parsing loop.
+func RegisterStreamParser(name string, p StreamParser) {
+	streamParsersMu.Lock()
+	defer streamParsersMu.Unlock()
+	for i, rp := range streamParsers {
+		if rp.name == name {
+			streamParsers[i].parser = p
+			return
+		}
+	}
+	streamParsers = append(streamParsers, registeredStreamParser{name: name, parser: p})
+}
+
+// streamParserSnapshot returns the currently registered parsers in the order
+// parseJSONStream should try them: registration order, except the generic
+// NDJSON fallback (see genericNDJSONParserName) is always moved to the end
+// regardless of when it was registered. That parser greedily claims any line
+// with a non-empty content/text/message field, so it must defer to every
+// other parser - including ones registered after it, such as a plugin
+// backend's custom schema - rather than shadowing them. Taking a snapshot
+// also lets ParseJSONStreamInternal iterate without holding the registry
+// lock for the duration of the stream.
+func streamParserSnapshot() []registeredStreamParser {
+	streamParsersMu.RLock()
+	defer streamParsersMu.RUnlock()
+	out := make([]registeredStreamParser, 0, len(streamParsers))
+	var lastResort []registeredStreamParser
+	for _, rp := range streamParsers {
+		if rp.name == genericNDJSONParserName {
+			lastResort = append(lastResort, rp)
+			continue
+		}
+		out = append(out, rp)
+	}
+	return append(out, lastResort...)
+}
+
+func init() {
+	// Opencode must be checked first: its events are otherwise ambiguous
+	// with Gemini's (both can carry a bare sessionID/content-shaped line).
+	RegisterStreamParser("opencode", opencodeStreamParser{})
+	RegisterStreamParser("gemini", geminiStreamParser{})
+	RegisterStreamParser("claude", claudeStreamParser{})
+	RegisterStreamParser("codex", codexStreamParser{})
+	RegisterStreamParser("openai", openAIStreamParser{})
+	// ndjson is last and only claims lines carrying content/text/message
+	// under no other backend's shape, so it never shadows the
+	// backend-specific parsers above in the default chain.
+	RegisterStreamParser("ndjson", genericNDJSONStreamParser{})
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-json"
+)
+
+// SchemaMapping configures a schema-driven StreamParser for backends whose
+// wire format isn't known to the Go code at compile time (plugin backends
+// declaring event_format: "custom" - see backend.LoadPlugins). It names
+// which top-level JSON fields of each event line play which role.
+type SchemaMapping struct {
+	// SessionIDField, if set, is the top-level string field holding the
+	// session/thread id.
+	SessionIDField string `json:"session_id_field,omitempty"`
+	// ContentField, if set, is the top-level string field holding message
+	// text to accumulate into the parser's output.
+	ContentField string `json:"content_field,omitempty"`
+	// CompleteField/CompleteValue, if both set, mark the turn as complete
+	// when that field equals that value.
+	CompleteField string `json:"complete_field,omitempty"`
+	CompleteValue string `json:"complete_value,omitempty"`
+	// DetectField/DetectValue let Detect claim only lines carrying a
+	// specific marker field/value, so a custom parser doesn't greedily
+	// claim every line in a mixed stream. An empty DetectField makes
+	// Detect always return true, so register such a parser last. With
+	// DetectField set and DetectValue left empty, Detect claims any line
+	// where the field is present with a non-empty value ("field must be
+	// present" rather than "field must equal this exact value"); set
+	// DetectValueMatchesEmpty to instead require the field be present and
+	// exactly equal to "".
+	DetectField             string `json:"detect_field,omitempty"`
+	DetectValue             string `json:"detect_value,omitempty"`
+	DetectValueMatchesEmpty bool   `json:"detect_value_matches_empty,omitempty"`
+}
+
+// schemaStreamParser is a StreamParser entirely driven by a SchemaMapping,
+// so a plugin backend can describe a new wire format via JSON config
+// instead of new Go code.
+type schemaStreamParser struct {
+	name    string
+	mapping SchemaMapping
+}
+
+// NewSchemaStreamParser returns a StreamParser for name driven by mapping.
+func NewSchemaStreamParser(name string, mapping SchemaMapping) StreamParser {
+	return schemaStreamParser{name: name, mapping: mapping}
+}
+
+func (p schemaStreamParser) Detect(rawLine []byte) bool {
+	if p.mapping.DetectField == "" {
+		return true
+	}
+	value, ok := schemaStringField(rawLine, p.mapping.DetectField)
+	if !ok {
+		return false
+	}
+	if p.mapping.DetectValue == "" && !p.mapping.DetectValueMatchesEmpty {
+		return value != ""
+	}
+	return value == p.mapping.DetectValue
+}
+
+func (p schemaStreamParser) HandleEvent(ctx *ParseCtx) error {
+	if p.mapping.SessionIDField != "" && ctx.ThreadID == "" {
+		if value, ok := schemaStringField(ctx.RawLine, p.mapping.SessionIDField); ok && value != "" {
+			ctx.ThreadID = value
+		}
+	}
+
+	if p.mapping.ContentField != "" {
+		if value, ok := schemaStringField(ctx.RawLine, p.mapping.ContentField); ok && value != "" {
+			ctx.Buffer(p.name).WriteString(value)
+			ctx.NotifyMessage()
+		}
+	}
+
+	if p.mapping.CompleteField != "" {
+		if value, ok := schemaStringField(ctx.RawLine, p.mapping.CompleteField); ok && value == p.mapping.CompleteValue {
+			ctx.NotifyComplete()
+		}
+	}
+
+	ctx.Info(fmt.Sprintf("Parsed %s (custom schema) event #%d", p.name, ctx.Index))
+	return nil
+}
+
+func (p schemaStreamParser) Finalize(ctx *ParseCtx) string {
+	return ctx.Buffer(p.name).String()
+}
+
+// schemaStringField extracts a top-level string field named key from a raw
+// JSON object line.
+func schemaStringField(rawLine []byte, key string) (string, bool) {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(rawLine, &fields) != nil {
+		return "", false
+	}
+	raw, ok := fields[key]
+	if !ok {
+		return "", false
+	}
+	var value string
+	if json.Unmarshal(raw, &value) != nil {
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaStreamParserHandlesCustomWireFormat(t *testing.T) {
+	mapping := SchemaMapping{
+		SessionIDField: "conversation_id",
+		ContentField:   "text",
+		CompleteField:  "state",
+		CompleteValue:  "done",
+		DetectField:    "conversation_id",
+	}
+
+	before := streamParserSnapshot()
+	defer func() {
+		streamParsersMu.Lock()
+		streamParsers = before
+		streamParsersMu.Unlock()
+	}()
+	RegisterStreamParser("plugin-test", NewSchemaStreamParser("plugin-test", mapping))
+
+	input := `{"conversation_id":"conv-1","text":"hello "}` + "\n" +
+		`{"conversation_id":"conv-1","text":"world"}` + "\n" +
+		`{"conversation_id":"conv-1","state":"done"}` + "\n"
+
+	msg, threadID := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "hello world" {
+		t.Fatalf("expected accumulated content %q, got %q", "hello world", msg)
+	}
+	if threadID != "conv-1" {
+		t.Fatalf("expected thread id conv-1, got %q", threadID)
+	}
+}
+
+func TestSchemaStreamParserDetectRequiresMarker(t *testing.T) {
+	mapping := SchemaMapping{DetectField: "marker", DetectValue: "plugin-x"}
+	p := NewSchemaStreamParser("plugin-x", mapping)
+
+	if p.Detect([]byte(`{"marker":"plugin-y"}`)) {
+		t.Fatalf("expected Detect to reject a mismatched marker value")
+	}
+	if !p.Detect([]byte(`{"marker":"plugin-x"}`)) {
+		t.Fatalf("expected Detect to accept a matching marker value")
+	}
+}
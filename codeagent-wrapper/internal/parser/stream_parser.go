@@ -0,0 +1,75 @@
+package parser
+
+import "strings"
+
+// ParseCtx carries the state shared across all StreamParser calls for a
+// single ParseJSONStreamInternal invocation: the decoded event, per-parser
+// scratch buffers for accumulating messages, and the warn/info/notify hooks
+// supplied by the caller. Reusing one ParseCtx per stream (rather than
+// storing accumulation state on the StreamParser itself) keeps registered
+// parsers stateless and safe to share across concurrent streams, e.g. when
+// parallel mode runs several tasks at once.
+type ParseCtx struct {
+	RawLine  []byte
+	Event    UnifiedEvent
+	Index    int
+	ThreadID string
+
+	scratch map[string]*strings.Builder
+
+	warnFn     func(string)
+	infoFn     func(string)
+	onMessage  func()
+	onComplete func()
+}
+
+// Warn reports a non-fatal parsing problem via the caller-supplied warn hook.
+func (c *ParseCtx) Warn(msg string) { c.warnFn(msg) }
+
+// Info reports a diagnostic message via the caller-supplied info hook.
+func (c *ParseCtx) Info(msg string) { c.infoFn(msg) }
+
+// NotifyMessage signals that a new partial/complete assistant message is available.
+func (c *ParseCtx) NotifyMessage() {
+	if c.onMessage != nil {
+		c.onMessage()
+	}
+}
+
+// NotifyComplete signals that the backend has finished the current turn.
+func (c *ParseCtx) NotifyComplete() {
+	if c.onComplete != nil {
+		c.onComplete()
+	}
+}
+
+// Buffer returns a scratch strings.Builder private to key (conventionally a
+// parser's registered name) that persists for the lifetime of the stream.
+// Parsers use it to accumulate or replace their candidate output message.
+func (c *ParseCtx) Buffer(key string) *strings.Builder {
+	if c.scratch == nil {
+		c.scratch = make(map[string]*strings.Builder)
+	}
+	b, ok := c.scratch[key]
+	if !ok {
+		b = &strings.Builder{}
+		c.scratch[key] = b
+	}
+	return b
+}
+
+// StreamParser handles one backend's JSON event stream format. Implementations
+// must be stateless; any state that needs to survive across events in a
+// single stream belongs in the ParseCtx passed to HandleEvent and Finalize.
+type StreamParser interface {
+	// Detect reports whether rawLine looks like an event produced by this
+	// backend. Detect is called in registration order until one parser
+	// claims the line, so it must be cheap and side-effect free.
+	Detect(rawLine []byte) bool
+	// HandleEvent processes one claimed event, updating ctx (thread ID,
+	// accumulated message, message/complete notifications).
+	HandleEvent(ctx *ParseCtx) error
+	// Finalize returns the parser's accumulated message once the stream
+	// ends, or "" if it produced none.
+	Finalize(ctx *ParseCtx) (message string)
+}
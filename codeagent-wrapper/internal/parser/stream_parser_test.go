@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+type testStreamParser struct {
+	tag string
+}
+
+func (p testStreamParser) Detect(rawLine []byte) bool      { return false }
+func (p testStreamParser) HandleEvent(ctx *ParseCtx) error { return nil }
+func (p testStreamParser) Finalize(ctx *ParseCtx) string   { return "" }
+
+// streamParserIndex returns the position of name in snap, or -1.
+func streamParserIndex(snap []registeredStreamParser, name string) int {
+	for i, rp := range snap {
+		if rp.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRegisterStreamParserOrderingAndReplace(t *testing.T) {
+	streamParsersMu.Lock()
+	before := make([]registeredStreamParser, len(streamParsers))
+	copy(before, streamParsers)
+	streamParsersMu.Unlock()
+	defer func() {
+		streamParsersMu.Lock()
+		streamParsers = before
+		streamParsersMu.Unlock()
+	}()
+
+	RegisterStreamParser("test-a", testStreamParser{tag: "a"})
+	RegisterStreamParser("test-b", testStreamParser{tag: "b"})
+
+	// streamParserSnapshot always moves the generic NDJSON fallback to the
+	// end (see its doc comment), so check test-a/test-b's relative order
+	// directly rather than assuming they're literally the last two entries.
+	snap := streamParserSnapshot()
+	idxA, idxB := streamParserIndex(snap, "test-a"), streamParserIndex(snap, "test-b")
+	if idxA == -1 || idxB == -1 || idxA >= idxB {
+		t.Fatalf("expected test-a registered before test-b, got %+v", snap)
+	}
+
+	RegisterStreamParser("test-a", testStreamParser{tag: "a-replaced"})
+	snap = streamParserSnapshot()
+	idxA = streamParserIndex(snap, "test-a")
+	got, ok := snap[idxA].parser.(testStreamParser)
+	if !ok || got.tag != "a-replaced" {
+		t.Fatalf("expected test-a's parser to be replaced in place, got %+v", snap[idxA])
+	}
+}
+
+func TestParseJSONStreamInternalDetectionOrderOpencodeFirst(t *testing.T) {
+	// This line is ambiguous: it has both an opencode sessionID/part and a
+	// Gemini-shaped role field. Opencode is registered first and must win.
+	input := `{"type":"text","sessionID":"sess-1","role":"assistant","part":{"type":"text","text":"hello"}}` + "\n"
+	msg, threadID := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "hello" {
+		t.Fatalf("expected opencode parser to win ambiguous line, got message %q", msg)
+	}
+	if threadID != "sess-1" {
+		t.Fatalf("expected thread id from opencode sessionID, got %q", threadID)
+	}
+}
+
+func TestParseJSONStreamInternalUnknownEventFallback(t *testing.T) {
+	input := "{\"type\":\"assistant\",\"foo\":\"bar\"}\n"
+	msg, threadID := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "" || threadID != "" {
+		t.Fatalf("expected unknown event to be ignored, got message=%q threadID=%q", msg, threadID)
+	}
+}
@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// useTokenizedStreamParser gates ParseJSONStreamInternal between its default
+// line-delimited path and ParseJSONStreamTokenized. It defaults to false
+// (disabled) so existing callers, benchmarks, and tests keep today's
+// behavior; see SetUseTokenizedStreamParser.
+var useTokenizedStreamParser atomic.Bool
+
+// SetUseTokenizedStreamParser switches ParseJSONStreamInternal to the
+// token-walking parser (see ParseJSONStreamTokenized) for the lifetime of
+// the process, or back to the line-delimited default when enabled is false.
+// It returns a restore func so tests and callers opting in temporarily can
+// put it back.
+func SetUseTokenizedStreamParser(enabled bool) (restore func()) {
+	prev := useTokenizedStreamParser.Swap(enabled)
+	return func() { useTokenizedStreamParser.Store(prev) }
+}
+
+// tokenizedEvent holds the fields decodeTopLevelEvent extracts from one
+// top-level object's tokens - just enough for ParseJSONStreamTokenized's
+// dispatch, without fully unmarshaling fields it doesn't need.
+type tokenizedEvent struct {
+	eventType string
+	threadID  string
+	itemText  string
+}
+
+// ParseJSONStreamTokenized is a token-walking alternative to the
+// line-delimited ParseJSONStreamInternal. Instead of assuming one complete
+// JSON object per line, it reads a sequence of top-level JSON values
+// directly off r via encoding/json.Decoder.Token(), so a backend that
+// pretty-prints an event across several lines - or embeds a multi-MB
+// tool-result payload - doesn't blow up a line-oriented reader's buffer.
+// For each top-level object it reads only the "type" (and, for
+// item.completed, "item") fields it cares about; every other field's value
+// is skipped token-by-token via skipValue rather than unmarshaled, which is
+// what keeps its allocations bounded regardless of how large an
+// uninteresting sub-object is.
+//
+// It currently understands the same Codex-shaped vocabulary
+// (thread.started/item.completed/thread.completed) that
+// ParseJSONStreamInternal's line-delimited path does; callers wanting the
+// broader multi-backend StreamParser registry should keep using
+// ParseJSONStreamInternal.
+func ParseJSONStreamTokenized(r io.Reader, warnFn func(string), infoFn func(string), onMessage func(), onComplete func()) (message, threadID string) {
+	if warnFn == nil {
+		warnFn = func(string) {}
+	}
+	if infoFn == nil {
+		infoFn = func(string) {}
+	}
+
+	dec := json.NewDecoder(r)
+	totalEvents := 0
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			warnFn("tokenized stream: " + err.Error())
+			break
+		}
+		delim, ok := tok.(json.Delim)
+		if !ok || delim != '{' {
+			// A stray top-level scalar; nothing of interest to dispatch on.
+			continue
+		}
+
+		ev, err := decodeTopLevelEvent(dec)
+		if err != nil {
+			warnFn("tokenized stream: failed to parse event: " + err.Error())
+			continue
+		}
+		totalEvents++
+
+		switch ev.eventType {
+		case "thread.started":
+			if ev.threadID != "" {
+				threadID = ev.threadID
+			}
+		case "item.completed":
+			if ev.itemText != "" {
+				message = ev.itemText
+				if onMessage != nil {
+					onMessage()
+				}
+			}
+		case "thread.completed":
+			if ev.threadID != "" {
+				threadID = ev.threadID
+			}
+			if onComplete != nil {
+				onComplete()
+			}
+		}
+	}
+
+	infoFn(fmt.Sprintf("parseJSONStreamTokenized completed: events=%d, message_len=%d, thread_id_found=%t", totalEvents, len(message), threadID != ""))
+	return message, threadID
+}
+
+// decodeTopLevelEvent walks one already-opened top-level object (its
+// leading '{' already consumed by the caller), extracting "type" and
+// "thread_id" cheaply and fully decoding "item" only once the object's type
+// is known to need it (item.completed). Every other field's value is
+// skipped via skipValue without being unmarshaled.
+func decodeTopLevelEvent(dec *json.Decoder) (tokenizedEvent, error) {
+	var ev tokenizedEvent
+	var rawItem json.RawMessage
+	haveItem := false
+
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return ev, err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return ev, fmt.Errorf("expected object key, got %v", keyTok)
+		}
+
+		switch key {
+		case "type":
+			v, err := dec.Token()
+			if err != nil {
+				return ev, err
+			}
+			if s, ok := v.(string); ok {
+				ev.eventType = s
+			}
+		case "thread_id":
+			v, err := dec.Token()
+			if err != nil {
+				return ev, err
+			}
+			if s, ok := v.(string); ok {
+				ev.threadID = s
+			}
+		case "item":
+			if err := dec.Decode(&rawItem); err != nil {
+				return ev, err
+			}
+			haveItem = true
+		default:
+			if err := skipValue(dec); err != nil {
+				return ev, err
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil { // closing '}'
+		return ev, err
+	}
+
+	if haveItem && ev.eventType == "item.completed" {
+		var item ItemContent
+		if json.Unmarshal(rawItem, &item) == nil && item.Type == "agent_message" {
+			ev.itemText = NormalizeText(item.Text)
+		}
+	}
+	return ev, nil
+}
+
+// skipValue consumes the next JSON value from dec without decoding it into
+// anything - a scalar is already fully consumed by its one Token() call; an
+// object or array is walked recursively, discarding its keys/elements, down
+// to matching closing delimiter.
+func skipValue(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '{' && delim != '[') {
+		return nil
+	}
+
+	for dec.More() {
+		if delim == '{' {
+			if _, err := dec.Token(); err != nil { // key
+				return err
+			}
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // closing delimiter
+	return err
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJSONStreamTokenizedBasicEvents(t *testing.T) {
+	input := `{"type":"thread.started","thread_id":"t-1"}
+{"type":"item.completed","item":{"type":"agent_message","text":"hello"}}
+{"type":"thread.completed","thread_id":"t-1"}
+`
+	msg, threadID := ParseJSONStreamTokenized(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "hello" {
+		t.Fatalf("message = %q, want %q", msg, "hello")
+	}
+	if threadID != "t-1" {
+		t.Fatalf("threadID = %q, want %q", threadID, "t-1")
+	}
+}
+
+func TestParseJSONStreamTokenizedHandlesPrettyPrintedMultiLineEvent(t *testing.T) {
+	input := `{
+  "type": "item.completed",
+  "item": {
+    "type": "agent_message",
+    "text": "multi-line hello"
+  }
+}
+`
+	msg, _ := ParseJSONStreamTokenized(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "multi-line hello" {
+		t.Fatalf("message = %q, want %q", msg, "multi-line hello")
+	}
+}
+
+func TestParseJSONStreamTokenizedSkipsLargeUninterestingFields(t *testing.T) {
+	input := `{"type":"item.completed","huge":{"nested":{"array":[1,2,3,["a","b",{"x":"y"}]]}},"item":{"type":"agent_message","text":"ok"}}`
+	msg, _ := ParseJSONStreamTokenized(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "ok" {
+		t.Fatalf("message = %q, want %q", msg, "ok")
+	}
+}
+
+func TestParseJSONStreamTokenizedNotifiesCallbacks(t *testing.T) {
+	var gotMessage, gotComplete bool
+	input := `{"type":"item.completed","item":{"type":"agent_message","text":"hi"}}
+{"type":"thread.completed"}
+`
+	ParseJSONStreamTokenized(strings.NewReader(input), nil, nil,
+		func() { gotMessage = true },
+		func() { gotComplete = true },
+	)
+	if !gotMessage {
+		t.Fatal("expected onMessage to fire for item.completed")
+	}
+	if !gotComplete {
+		t.Fatal("expected onComplete to fire for thread.completed")
+	}
+}
+
+func TestSetUseTokenizedStreamParserRoutesParseJSONStreamInternal(t *testing.T) {
+	restore := SetUseTokenizedStreamParser(true)
+	defer restore()
+
+	input := `{"type":"item.completed","item":{"type":"agent_message","text":"tokenized"}}` + "\n"
+	msg, _ := ParseJSONStreamInternal(strings.NewReader(input), nil, nil, nil, nil)
+	if msg != "tokenized" {
+		t.Fatalf("message = %q, want %q (tokenized path should have been used)", msg, "tokenized")
+	}
+}
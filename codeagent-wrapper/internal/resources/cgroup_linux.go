@@ -0,0 +1,153 @@
+//go:build linux
+
+package resources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// cgroupRoot and scopeParent are vars (not consts) so tests can point them
+// at a t.TempDir() fixture instead of the real /sys/fs/cgroup.
+var (
+	cgroupRoot  = "/sys/fs/cgroup"
+	scopeParent = "codeagent-wrapper"
+)
+
+// Apply places pid into a transient cgroup scope named <taskID>.scope,
+// preferring cgroup v2 and falling back to cgroup v1's separate
+// cpu/memory/blkio/pids hierarchies when v2 isn't mounted. The returned
+// cleanup func removes the scope directory/directories; callers should run
+// it once the process has exited (see ProcessHandle's kill/wait path) -
+// cgroupfs refuses to rmdir a non-empty scope.
+func Apply(taskID string, pid int, limits Limits) (cleanup func(), err error) {
+	if limits.IsZero() {
+		return func() {}, nil
+	}
+	if err := limits.Validate(); err != nil {
+		return func() {}, err
+	}
+
+	if isCgroupV2() {
+		return applyV2(taskID, pid, limits)
+	}
+	return applyV1(taskID, pid, limits)
+}
+
+func isCgroupV2() bool {
+	_, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	return err == nil
+}
+
+func applyV2(taskID string, pid int, limits Limits) (func(), error) {
+	scope := filepath.Join(cgroupRoot, scopeParent, taskID+".scope")
+	if err := os.MkdirAll(scope, 0o755); err != nil {
+		return func() {}, fmt.Errorf("resources: create cgroup v2 scope: %w", err)
+	}
+	cleanup := func() { _ = os.Remove(scope) }
+
+	if limits.CPUQuota > 0 {
+		const period = 100000
+		tryWrite(filepath.Join(scope, "cpu.max"), fmt.Sprintf("%d %d", limits.CPUQuota, period))
+	}
+	if limits.CPUShares > 0 {
+		tryWrite(filepath.Join(scope, "cpu.weight"), strconv.FormatInt(cpuSharesToWeight(limits.CPUShares), 10))
+	}
+	if limits.MemoryLimit > 0 {
+		tryWrite(filepath.Join(scope, "memory.max"), strconv.FormatInt(limits.MemoryLimit, 10))
+	}
+	if limits.MemoryReservation > 0 {
+		tryWrite(filepath.Join(scope, "memory.high"), strconv.FormatInt(limits.MemoryReservation, 10))
+	}
+	if limits.BlkioWeight > 0 {
+		tryWrite(filepath.Join(scope, "io.weight"), strconv.FormatInt(limits.BlkioWeight, 10))
+	}
+	if limits.PIDsMax > 0 {
+		tryWrite(filepath.Join(scope, "pids.max"), strconv.FormatInt(limits.PIDsMax, 10))
+	}
+
+	if err := os.WriteFile(filepath.Join(scope, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o644); err != nil { // #nosec G306 -- cgroupfs control file, not a secret
+		return cleanup, fmt.Errorf("resources: move pid %d into cgroup: %w", pid, err)
+	}
+
+	return cleanup, nil
+}
+
+func applyV1(taskID string, pid int, limits Limits) (func(), error) {
+	var cleanups []func()
+	cleanup := func() {
+		for _, c := range cleanups {
+			c()
+		}
+	}
+
+	controller := func(name string, set func(dir string)) {
+		dir := filepath.Join(cgroupRoot, name, scopeParent, taskID+".scope")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logWarnFn(fmt.Sprintf("resources: create %s cgroup v1 scope: %s", name, err))
+			return
+		}
+		cleanups = append(cleanups, func() { _ = os.Remove(dir) })
+		set(dir)
+		if err := os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o644); err != nil { // #nosec G306 -- cgroupfs control file, not a secret
+			logWarnFn(fmt.Sprintf("resources: move pid into %s cgroup v1: %s", name, err))
+		}
+	}
+
+	if limits.CPUShares > 0 || limits.CPUQuota > 0 {
+		controller("cpu", func(dir string) {
+			if limits.CPUShares > 0 {
+				tryWrite(filepath.Join(dir, "cpu.shares"), strconv.FormatInt(limits.CPUShares, 10))
+			}
+			if limits.CPUQuota > 0 {
+				tryWrite(filepath.Join(dir, "cpu.cfs_quota_us"), strconv.FormatInt(limits.CPUQuota, 10))
+				tryWrite(filepath.Join(dir, "cpu.cfs_period_us"), "100000")
+			}
+		})
+	}
+
+	if limits.MemoryLimit > 0 || limits.MemoryReservation > 0 {
+		controller("memory", func(dir string) {
+			if limits.MemoryLimit > 0 {
+				tryWrite(filepath.Join(dir, "memory.limit_in_bytes"), strconv.FormatInt(limits.MemoryLimit, 10))
+			}
+			if limits.MemoryReservation > 0 {
+				tryWrite(filepath.Join(dir, "memory.soft_limit_in_bytes"), strconv.FormatInt(limits.MemoryReservation, 10))
+			}
+		})
+	}
+
+	if limits.BlkioWeight > 0 {
+		controller("blkio", func(dir string) {
+			tryWrite(filepath.Join(dir, "blkio.weight"), strconv.FormatInt(limits.BlkioWeight, 10))
+		})
+	}
+
+	if limits.PIDsMax > 0 {
+		controller("pids", func(dir string) {
+			tryWrite(filepath.Join(dir, "pids.max"), strconv.FormatInt(limits.PIDsMax, 10))
+		})
+	}
+
+	return cleanup, nil
+}
+
+// cpuSharesToWeight converts an OCI cpu_shares value (2-262144) to a cgroup
+// v2 cpu.weight value (1-10000), using the same linear mapping runc uses.
+func cpuSharesToWeight(shares int64) int64 {
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
+
+func tryWrite(path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil { // #nosec G306 -- cgroupfs control file, not a secret
+		logWarnFn(fmt.Sprintf("resources: write %s: %s", path, err))
+	}
+}
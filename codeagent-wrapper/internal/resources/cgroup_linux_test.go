@@ -0,0 +1,63 @@
+//go:build linux
+
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyV2WritesControlFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory io pids"), 0o644); err != nil {
+		t.Fatalf("fixture setup: %v", err)
+	}
+
+	origRoot := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = origRoot })
+
+	limits := Limits{CPUShares: 512, MemoryLimit: 1 << 20, PIDsMax: 50}
+	cleanup, err := Apply("task-1", os.Getpid(), limits)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	defer cleanup()
+
+	scope := filepath.Join(root, scopeParent, "task-1.scope")
+	for _, f := range []string{"cpu.weight", "memory.max", "pids.max", "cgroup.procs"} {
+		if _, err := os.Stat(filepath.Join(scope, f)); err != nil {
+			t.Errorf("expected %s to exist: %v", f, err)
+		}
+	}
+}
+
+func TestApplyRejectsInvalidLimits(t *testing.T) {
+	root := t.TempDir()
+	origRoot := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = origRoot })
+
+	limits := Limits{MemoryLimit: 100, MemoryReservation: 200}
+	if _, err := Apply("task-2", os.Getpid(), limits); err == nil {
+		t.Fatalf("expected validation error, got nil")
+	}
+}
+
+func TestApplyZeroLimitsIsNoop(t *testing.T) {
+	cleanup, err := Apply("task-3", os.Getpid(), Limits{})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	cleanup()
+}
+
+func TestCPUSharesToWeightClamps(t *testing.T) {
+	if w := cpuSharesToWeight(0); w != 1 {
+		t.Errorf("expected minimum weight 1 for shares below range, got %d", w)
+	}
+	if w := cpuSharesToWeight(1 << 30); w != 10000 {
+		t.Errorf("expected maximum weight 10000 for shares above range, got %d", w)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package resources
+
+import "fmt"
+
+// Apply has no functional enforcement on non-Linux platforms: there is no
+// portable stdlib mechanism to cap CPU/memory/IO/pids on an already-running
+// foreign pid outside of Linux cgroups, so we log what was requested and
+// skip it rather than pretending to enforce it.
+func Apply(taskID string, pid int, limits Limits) (cleanup func(), err error) {
+	if limits.IsZero() {
+		return func() {}, nil
+	}
+	if err := limits.Validate(); err != nil {
+		return func() {}, err
+	}
+
+	logWarnFn(fmt.Sprintf("resources: no cgroup support on this platform, limits for task %q (pid %d) will not be enforced", taskID, pid))
+	return func() {}, nil
+}
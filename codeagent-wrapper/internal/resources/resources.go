@@ -0,0 +1,47 @@
+// Package resources applies per-task CPU/memory/IO/process-count caps to a
+// running child process through Linux cgroups (v2, or v1 when v2 isn't
+// mounted). On other platforms Apply only validates the limits and logs a
+// warning; no cap is enforced there.
+package resources
+
+import "errors"
+
+var errMemoryReservationExceedsLimit = errors.New("memory_reservation must not exceed memory_limit")
+
+// Limits mirrors the containerd/OCI resource fields a TaskSpec can declare.
+// A zero value in any field means "no limit for that knob".
+type Limits struct {
+	CPUShares         int64 // OCI cpu_shares (2-262144), maps to cgroup v1 cpu.shares / v2 cpu.weight
+	CPUQuota          int64 // microseconds of CPU time allowed per 100ms period (cgroup v1 cpu.cfs_quota_us / v2 cpu.max)
+	MemoryLimit       int64 // hard memory cap in bytes (cgroup memory.max / memory.limit_in_bytes)
+	MemoryReservation int64 // soft memory target in bytes (cgroup memory.high / memory.soft_limit_in_bytes)
+	BlkioWeight       int64 // relative block IO weight, 10-1000 (cgroup io.weight / blkio.weight)
+	PIDsMax           int64 // max number of tasks/threads (cgroup pids.max)
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l == (Limits{})
+}
+
+// Validate rejects combinations that can never be satisfied, mirroring the
+// checks Docker/containerd apply before creating a container.
+func (l Limits) Validate() error {
+	if l.MemoryLimit > 0 && l.MemoryReservation > 0 && l.MemoryReservation > l.MemoryLimit {
+		return errMemoryReservationExceedsLimit
+	}
+	return nil
+}
+
+var logWarnFn = func(string) {}
+
+// SetLogFuncs configures the optional warning hook used when a requested
+// limit can't be enforced on the current platform. Callers can safely pass
+// nil to disable it.
+func SetLogFuncs(warnFn func(string)) {
+	if warnFn != nil {
+		logWarnFn = warnFn
+	} else {
+		logWarnFn = func(string) {}
+	}
+}
@@ -0,0 +1,52 @@
+package resources
+
+import "testing"
+
+func TestLimitsIsZero(t *testing.T) {
+	if !(Limits{}).IsZero() {
+		t.Fatalf("expected zero-value Limits to report IsZero")
+	}
+	if (Limits{CPUShares: 1024}).IsZero() {
+		t.Fatalf("expected non-zero Limits to report !IsZero")
+	}
+}
+
+func TestLimitsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		limits  Limits
+		wantErr bool
+	}{
+		{"no limits", Limits{}, false},
+		{"limit only", Limits{MemoryLimit: 1 << 20}, false},
+		{"reservation below limit", Limits{MemoryLimit: 1 << 20, MemoryReservation: 1 << 19}, false},
+		{"reservation equals limit", Limits{MemoryLimit: 1 << 20, MemoryReservation: 1 << 20}, false},
+		{"reservation exceeds limit", Limits{MemoryLimit: 1 << 19, MemoryReservation: 1 << 20}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.limits.Validate()
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetLogFuncs(t *testing.T) {
+	var captured string
+	SetLogFuncs(func(msg string) { captured = msg })
+	t.Cleanup(func() { SetLogFuncs(nil) })
+
+	logWarnFn("hello")
+	if captured != "hello" {
+		t.Fatalf("expected logWarnFn to route through configured hook, got %q", captured)
+	}
+
+	SetLogFuncs(nil)
+	logWarnFn("ignored")
+}
@@ -0,0 +1,177 @@
+// Command benchcmp runs this module's Benchmark* functions and fails CI
+// when a benchmark regresses beyond its configured budget. It is meant to
+// be invoked as `go run ./scripts/benchcmp` from the module root, after the
+// three quality-gate commands (go build/vet/test) already pass.
+//
+// Budgets are checked in at scripts/benchcmp/budgets.yaml, one entry per
+// benchmark name, each with an allowed ns/op regression percentage and a
+// ceiling on allocs/op. Run with -update to (re)write that file from the
+// current measurements, e.g. after intentionally changing a hot path's
+// allocation profile.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/benchmark/parse"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "benchcmp:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("benchcmp", flag.ContinueOnError)
+	pkg := fs.String("pkg", "./...", "package pattern passed to 'go test -bench'")
+	benchRegex := fs.String("bench", ".", "-bench regex passed to 'go test'")
+	budgetsPath := fs.String("budgets", "scripts/benchcmp/budgets.yaml", "path to the checked-in benchmark budgets file")
+	update := fs.Bool("update", false, "write current measurements to -budgets instead of comparing against it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	out, err := runBenchmarks(*pkg, *benchRegex)
+	if err != nil {
+		return fmt.Errorf("run benchmarks: %w", err)
+	}
+
+	current, err := parse.ParseSet(bytes.NewReader(out))
+	if err != nil {
+		return fmt.Errorf("parse benchmark output: %w", err)
+	}
+
+	if *update {
+		return writeBudgets(*budgetsPath, current)
+	}
+
+	budgets, err := loadBudgets(*budgetsPath)
+	if err != nil {
+		return fmt.Errorf("load budgets %s: %w", *budgetsPath, err)
+	}
+
+	failures := checkBudgets(budgets, current)
+	for _, f := range failures {
+		fmt.Fprintln(os.Stderr, f)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d benchmark(s) regressed beyond budget", len(failures))
+	}
+	return nil
+}
+
+// runBenchmarks shells out to `go test` rather than calling testing.Main
+// directly, so benchcmp measures the exact same Benchmark* functions a
+// developer or CI would run by hand, with no special harness in between.
+func runBenchmarks(pkg, benchRegex string) ([]byte, error) {
+	cmd := exec.Command("go", "test", "-run=^$", "-bench="+benchRegex, "-benchmem", pkg)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, out)
+	}
+	return out, nil
+}
+
+// Budget is one benchmark's allowed regression window, keyed by benchmark
+// name in the checked-in budgets.yaml.
+type Budget struct {
+	NsPerOp         float64 `yaml:"ns_per_op"`
+	MaxNsRegressPct float64 `yaml:"max_ns_regress_pct"`
+	AllocsPerOp     uint64  `yaml:"allocs_per_op"`
+	AllowNewAllocs  bool    `yaml:"allow_new_allocs,omitempty"`
+}
+
+func loadBudgets(path string) (map[string]Budget, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is an operator-supplied CLI flag, not untrusted input
+	if err != nil {
+		return nil, err
+	}
+	var budgets map[string]Budget
+	if err := yaml.Unmarshal(data, &budgets); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return budgets, nil
+}
+
+func writeBudgets(path string, current parse.Set) error {
+	budgets := make(map[string]Budget, len(current))
+	for name, benches := range current {
+		if len(benches) == 0 {
+			continue
+		}
+		b := benches[0]
+		budgets[name] = Budget{
+			NsPerOp:         b.NsPerOp,
+			MaxNsRegressPct: 10,
+			AllocsPerOp:     b.AllocsPerOp,
+		}
+	}
+
+	data, err := yaml.Marshal(budgets)
+	if err != nil {
+		return fmt.Errorf("encode budgets: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// checkBudgets compares current against budgets, returning one human-readable
+// failure line per benchmark that regressed. A benchmark with no budget
+// entry is reported but not treated as a failure - a freshly added benchmark
+// shouldn't fail CI before -update has ever run for it.
+func checkBudgets(budgets map[string]Budget, current parse.Set) []string {
+	var failures []string
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		benches := current[name]
+		if len(benches) == 0 {
+			continue
+		}
+		b := benches[0]
+
+		budget, ok := budgets[name]
+		if !ok {
+			// go test names a benchmark "BenchmarkX-<GOMAXPROCS>" when
+			// GOMAXPROCS > 1; budgets.yaml may key it by the bare name.
+			if i := strings.LastIndex(name, "-"); i > 0 {
+				if _, err := strconv.Atoi(name[i+1:]); err == nil {
+					budget, ok = budgets[name[:i]]
+				}
+			}
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "benchcmp: %s has no budget entry yet; run with -update\n", name)
+			continue
+		}
+
+		if budget.NsPerOp > 0 {
+			regressPct := (b.NsPerOp - budget.NsPerOp) / budget.NsPerOp * 100
+			if regressPct > budget.MaxNsRegressPct {
+				failures = append(failures, fmt.Sprintf(
+					"%s: ns/op regressed %.1f%% (budget %.1f%%): %.0f -> %.0f",
+					name, regressPct, budget.MaxNsRegressPct, budget.NsPerOp, b.NsPerOp))
+			}
+		}
+
+		if !budget.AllowNewAllocs && b.AllocsPerOp > budget.AllocsPerOp {
+			failures = append(failures, fmt.Sprintf(
+				"%s: allocs/op regressed: %d -> %d",
+				name, budget.AllocsPerOp, b.AllocsPerOp))
+		}
+	}
+	return failures
+}